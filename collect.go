@@ -0,0 +1,41 @@
+//go:build go1.18
+// +build go1.18
+
+package paginate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Collect scans all the paginated data of the given Paginator into a slice of
+// the table type T, driving NextData and Scan, e.g.:
+//
+//  if err := paginator.Execute(ctx, db); err != nil {
+//     // Handle error gracefully.
+//  }
+//  employees, err := Collect[Employee](paginator)
+//
+// T should be the type of the table given to NewPaginator. Collect returns
+// ErrPaginatorIsClosed when the data of the Paginator has already been scanned.
+func Collect[T any](p Paginator) ([]T, error) {
+	if pag, ok := p.(*paginator); ok {
+		if t := reflect.TypeOf((*T)(nil)).Elem(); t != pag.rv.Type() {
+			return nil, fmt.Errorf("paginate: cannot collect rows of type %s from a paginator of type %s",
+				t.String(), pag.rv.Type().String())
+		}
+		if pag.closed {
+			return nil, ErrPaginatorIsClosed
+		}
+	}
+
+	results := make([]T, 0)
+	for p.NextData() {
+		var row T
+		if err := p.Scan(&row); err != nil {
+			return nil, err
+		}
+		results = append(results, row)
+	}
+	return results, nil
+}
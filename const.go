@@ -5,6 +5,34 @@ const (
 	defaultPageSize   = 30
 	defaultPageNumber = 1
 	tagsep            = ";"
+
+	// defaultPageParam and defaultPageSizeParam are the names of the
+	// request parameters holding the page number and the page size.
+	defaultPageParam     = "page"
+	defaultPageSizeParam = "page_size"
+
+	// defaultLimitParam and defaultOffsetParam are the names of the request
+	// parameters holding the page size and the number of records to skip
+	// when the LimitOffset option is used, e.g. ``limit=10&offset=40``.
+	defaultLimitParam  = "limit"
+	defaultOffsetParam = "offset"
+
+	// defaultSortParam is the name of the request parameter
+	// holding the sort directives, e.g. ``sort=+name,-age``.
+	defaultSortParam = "sort"
+
+	// defaultFieldsParam is the name of the request parameter holding the
+	// fields that should be selected, e.g. ``fields=id,name``. See the
+	// AllowSparseFields option.
+	defaultFieldsParam = "fields"
+
+	// rangesep separates the lower and upper bounds of a range
+	// given in the request url, e.g. ``salary=4000..8000``.
+	rangesep = ".."
+
+	// defaultLastModifiedColumn is the name of the column that
+	// Paginator will use to compute Paginator.LastModified.
+	defaultLastModifiedColumn = "updated_at"
 )
 
 // Constants that specify the available filter operators.
@@ -16,6 +44,14 @@ const (
 	gte = ">="
 	lte = "<="
 	ne  = "<>"
+
+	// contains is used to match the records whose column value contains
+	// the given value, e.g. ``name=~ringo``. See the _like sign.
+	contains = "=~"
+
+	// notcontains is used to match the records whose column value does not
+	// contain the given value, e.g. ``name=!~ringo``. See the _notlike sign.
+	notcontains = "=!~"
 )
 
 // Constants that represent the IN and NOT IN sql clauses.
@@ -27,6 +63,53 @@ const (
 	_notin = "NOT IN"
 )
 
+// Constants that represent the IS NULL and IS NOT NULL sql clauses. We will
+// use these whenever a parameter in the url with the eq sign has the value
+// ``null`` or ``notnull`` and the column is nullable, e.g. ``null_date=null``,
+// but not the quoted values ``"null"`` or ``"notnull"``. For more info check getParameters and createWhereClause.
+const (
+	_isnull    = "IS NULL"
+	_isnotnull = "IS NOT NULL"
+)
+
+// Constant that represents the LIKE sql clause (ILIKE for postgres). We will
+// use it whenever a parameter in the url has the contains operator, e.g.
+// ``name=~ringo``. For more info check getParameters and createWhereClause.
+const _like = "LIKE"
+
+// Constant that represents the NOT LIKE sql clause (NOT ILIKE for postgres). We
+// will use it whenever a parameter in the url has the notcontains operator, e.g.
+// ``name=!~ringo``. For more info check getParameters and createWhereClause.
+const _notlike = "NOT LIKE"
+
+// Constant that represents the placeholder of the arguments used while building
+// the sql commands. The placeholders are rendered with the placeholderStyle of the
+// Paginator at the end, e.g. as $1, $2, etc. for postgres. See renderPlaceholders.
+const _placeholder = "?"
+
+// Constants that represent the sql conjunctions that can be used to combine
+// the filters of the request url. See Paginator.SetFilterConjunction.
+const (
+	_and = "AND"
+	_or  = "OR"
+)
+
+// Constants that represent the sql clauses used to filter postgres array columns.
+// We will use _any whenever a parameter of an array column in the url has the eq
+// sign, e.g. ``tags=go`` will match the arrays containing "go", and _overlap when
+// the parameter is repeated, e.g. ``tags=go&tags=rust`` will match the arrays
+// containing "go" or "rust". For more info check setArraySigns and createWhereClause.
+const (
+	_any     = "ANY"
+	_overlap = "&&"
+)
+
+// Constant that represents the BETWEEN sql clause. We will use it
+// whenever a parameter in the url with the eq sign has a range value,
+// e.g. ``salary=4000..8000``. For more info check getParameters and
+// createWhereClause.
+const _between = "BETWEEN"
+
 // Constants that represent the struct field tags available
 // for the package.
 const (
@@ -37,4 +120,18 @@ const (
 	param = "param"
 	// We use filter to determine which columns need to be filtered.
 	filter = "filter"
+	// We use fn to apply an sql function to a column when filtering it,
+	// e.g. "fn=DATE" will filter the column with DATE(column).
+	fn = "fn"
+	// We use _json to filter a jsonb column by the keys of its json
+	// values (postgres only), e.g. ``meta.role=admin`` will filter the
+	// column with meta->>'role'.
+	_json = "json"
+	// We use _array to filter a postgres array column by the elements of
+	// its values, e.g. ``tags=go`` will filter the column with $1 = ANY(tags).
+	_array = "array"
+	// We use noselect to filter or sort the records by a column without
+	// selecting it, e.g. "filter;noselect;col=org_id". The field will be
+	// left with its zero value by Scan.
+	noselect = "noselect"
 )
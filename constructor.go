@@ -2,9 +2,11 @@ package paginate
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 )
 
 type Option func(p *paginator) error
@@ -41,11 +43,259 @@ func PageSize(size uint) Option {
 	}
 }
 
+// MaxPageSize is an option for NewPaginator that limits the page size to the given
+// size. Bigger page sizes, e.g. ``page_size=1000000``, will be reduced to the given
+// size and PaginationResponse.PageSizeClamped will be true.
+func MaxPageSize(size uint) Option {
+	return func(p *paginator) error {
+		if size == 0 {
+			return fmt.Errorf("paginate: max page size should be greater than zero")
+		}
+		p.maxPageSize = int(size)
+		return nil
+	}
+}
+
+// MaxOffset is an option for NewPaginator that limits the number of records that can
+// be skipped to reach the requested page, since big offsets are slow in most databases.
+// Pages beyond the given offset will be reduced to the last page within the offset and
+// PaginationResponse.OffsetClamped will be true. With the StrictPaging option these
+// pages will be rejected with ErrOffsetTooDeep instead.
+func MaxOffset(offset uint) Option {
+	return func(p *paginator) error {
+		p.maxOffset = int(offset)
+		p.hasMaxOffset = true
+		return nil
+	}
+}
+
+// ColumnNamer is an option for NewPaginator that changes how the column names of the fields
+// of the table without the tag "col" are inferred from their names. By default the names are
+// converted to snake case, e.g. "LastName" will be "last_name". For example, a namer can keep
+// the names as they are or add a prefix to them:
+//
+//	paginate.ColumnNamer(func(fieldName string) string {
+//		return "emp_" + strings.ToLower(fieldName)
+//	})
+//
+// The inferred names should be valid column names, otherwise NewPaginator will return an error.
+func ColumnNamer(namer func(fieldName string) string) Option {
+	return func(p *paginator) error {
+		if namer == nil {
+			return fmt.Errorf("paginate: column namer should not be nil")
+		}
+		p.columnNamer = namer
+		return nil
+	}
+}
+
+// PageParam is an option for NewPaginator which indicates the name of the request
+// parameter holding the page number. By default Paginator will use ``page``.
+func PageParam(name string) Option {
+	return func(p *paginator) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("paginate: page parameter should not be an empty string")
+		}
+		p.pageParam = name
+		return nil
+	}
+}
+
+// LimitOffset is an option for NewPaginator that lets clients paginate the records with the
+// ``limit`` and ``offset`` request parameters, e.g. ``?limit=10&offset=40``, instead of the
+// page number and the page size. The limit will be used as the page size and the records
+// will be skipped by the given offset, which does not need to be a multiple of the limit.
+// PaginationResponse.PageNumber will be the page where the first record of the offset is.
+// The limit and the offset take precedence over the page size and the page parameters.
+// With the StrictPaging option giving both of them, or giving invalid values, will make
+// NewPaginator return an error. Paginator.SetPage goes back to the page-based pagination.
+func LimitOffset() Option {
+	return func(p *paginator) error {
+		p.limitOffset = true
+		return nil
+	}
+}
+
+// PageSizeParam is an option for NewPaginator which indicates the name of the request
+// parameter holding the page size. By default Paginator will use ``page_size``.
+func PageSizeParam(name string) Option {
+	return func(p *paginator) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("paginate: page size parameter should not be an empty string")
+		}
+		p.pageSizeParam = name
+		return nil
+	}
+}
+
+// GroupBy is an option for NewPaginator that adds an sql GROUP BY clause with the given
+// columns to the query, e.g. GroupBy("department") will produce:
+//
+//  SELECT department, count(*) over() FROM employees GROUP BY department ORDER BY department LIMIT 30 OFFSET 0
+//
+// The given columns should be columns of the given table, otherwise NewPaginator will
+// return an error. Note that every column of the given table, including its "id", should
+// be one of the given columns or be functionally dependent on them, and that the total
+// size of the pagination will be the number of groups instead of the number of records.
+func GroupBy(columns ...string) Option {
+	return func(p *paginator) error {
+		if len(columns) == 0 {
+			return fmt.Errorf("paginate: GroupBy requires at least one column")
+		}
+		p.groupBy = append(p.groupBy, columns...)
+		return nil
+	}
+}
+
+// Columns is an option for NewPaginator that restricts the columns selected in the sql
+// command to the given fields of the table. The fields can be given by their struct
+// field name or by their column name, e.g. Columns("Name", "last_name"). The "id" of
+// the table will always be selected. The fields that are not selected will be left
+// with their zero values by Scan. The columns that are not selected can still be used
+// to filter and sort the records.
+func Columns(fields ...string) Option {
+	return func(p *paginator) error {
+		if len(fields) == 0 {
+			return fmt.Errorf("paginate: Columns requires at least one field")
+		}
+		p.projection = append(p.projection, fields...)
+		return nil
+	}
+}
+
+// FieldsParam is an option for NewPaginator which indicates the name of the request
+// parameter that clients can use to choose the fields of the table that should be
+// selected, e.g. ``fields=name,last_name``. A leading "-" excludes the given fields
+// from the selection instead, e.g. ``fields=-notes,-metadata`` will select all the
+// fields except notes and metadata. Selecting and excluding fields in the same
+// request is not allowed. The fields can be given by their column name or by their
+// struct field name, and the "id" of the table will always be selected.
+func FieldsParam(name string) Option {
+	return func(p *paginator) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("paginate: fields parameter should not be an empty string")
+		}
+		p.fieldsParam = name
+		return nil
+	}
+}
+
+// AllowSparseFields is an option for NewPaginator that lets clients choose the fields of
+// the table that should be selected with the request parameter ``fields``, following the
+// JSON:API sparse fieldsets convention, e.g. ``fields=id,name``. It works like the
+// FieldsParam option with the parameter ``fields``, except that the requested fields
+// that are not fields or columns of the table are silently ignored. The "id" of the
+// table will always be selected.
+func AllowSparseFields() Option {
+	return func(p *paginator) error {
+		p.fieldsParam = defaultFieldsParam
+		p.ignoreUnknownFields = true
+		return nil
+	}
+}
+
+// ExcludeColumns is an option for NewPaginator that prevents the given fields of the
+// table from ever being selected, filtered or sorted by Paginator, e.g. a field mapped
+// to a ``password_hash`` column. The fields can be given by their struct field name
+// or by their column name. The excluded fields will be left with their zero values by
+// Scan. The "id" of the table cannot be excluded since Paginator sorts by it.
+func ExcludeColumns(fields ...string) Option {
+	return func(p *paginator) error {
+		p.excluded = append(p.excluded, fields...)
+		return nil
+	}
+}
+
+// Distinct is an option for NewPaginator that makes Paginator select only distinct
+// records, e.g. to remove the duplicated records of the paginated table produced by
+// joining a one-to-many table:
+//
+//  SELECT DISTINCT id, name, count(*) over() FROM employees LEFT JOIN ... GROUP BY id, name ORDER BY id LIMIT 30 OFFSET 0
+//
+// Since count(*) over() is computed before removing the duplicated records, Paginator
+// will also group the records by all the selected columns, so the total size of the
+// pagination will be the number of distinct records.
+func Distinct() Option {
+	return func(p *paginator) error {
+		p.distinct = true
+		return nil
+	}
+}
+
+// ConcurrentCount is an option for NewPaginator that makes Paginator.Execute run the
+// separate count query concurrently with the query of the page, e.g. for the mariadb
+// dialect, instead of running them one after the other. If any of the queries fails
+// the other one will be cancelled through its context. The Querier given to Execute
+// should be safe for concurrent use, like *sql.DB; a *sql.Tx is not.
+func ConcurrentCount() Option {
+	return func(p *paginator) error {
+		p.concurrentCount = true
+		return nil
+	}
+}
+
+// TimeLayout is an option for NewPaginator that sets the layout (see the time package)
+// of the timestamps given in the request to filter the time columns of the table, i.e.
+// the columns of the fields of type time.Time, *time.Time or NullTime. The timestamps
+// will be parsed with the given layout and given to the database as time.Time values,
+// so for example given the option TimeLayout("2006-01-02") and a request url like:
+//
+//	http://localhost/employees?date_joined>2021-01-02
+//
+// the records will be filtered with the time 2021-01-02 00:00:00 in UTC, or in the time
+// zone given with the Location option. Paginator.Paginate will return an error when any
+// of the timestamps does not have the given layout.
+func TimeLayout(layout string) Option {
+	return func(p *paginator) error {
+		if strings.TrimSpace(layout) == "" {
+			return fmt.Errorf("paginate: time layout should not be an empty string")
+		}
+		p.timeLayout = layout
+		return nil
+	}
+}
+
+// Location is an option for NewPaginator that sets the time zone used by Paginator
+// for the time columns of the table, i.e. the columns of the fields of type time.Time,
+// *time.Time or NullTime. The timestamps given without a time zone to filter these
+// columns, e.g. ``date_joined>2021-01-31 10:00:00``, will be interpreted in the given
+// location, while timestamps with a time zone, e.g. ``2021-01-31T10:00:00+02:00``,
+// will keep their time zone. The scanned times will also be converted to the given
+// location, so they are consistent across dialects and drivers.
+func Location(loc *time.Location) Option {
+	return func(p *paginator) error {
+		if loc == nil {
+			return fmt.Errorf("paginate: location should not be nil")
+		}
+		p.location = loc
+		return nil
+	}
+}
+
+// SortParam is an option for NewPaginator which indicates the name of the request
+// parameter holding the sort directives, e.g. ``order_by=+name,-age``. By default
+// Paginator will use ``sort``.
+func SortParam(name string) Option {
+	return func(p *paginator) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("paginate: sort parameter should not be an empty string")
+		}
+		p.sortParam = name
+		return nil
+	}
+}
+
 // OrderByAsc is an option for NewPaginator that allows you to add a custom specific
 // sql ascending ORDER BY clause. This is useful when, for example, you want to have control
 // on the sorting from the backend. Trying to sort by the "id" defined in the given table
 // (through the tag "id") will not work, since Paginator will always sort the results in
 // a deterministic way, so it will not consider the given "id" for sorting.
+// The given column should be one of the columns of the given table, otherwise
+// NewPaginator will return an error.
 func OrderByAsc(column string) Option {
 	return func(p *paginator) error {
 		p.orderByClauses = append(p.orderByClauses, orderByClause{
@@ -61,6 +311,8 @@ func OrderByAsc(column string) Option {
 // on the sorting from the backend. Trying to sort by the "id" defined in the given table
 // (through the tag "id") will not work, since Paginator will always sort the results in
 // a deterministic way, so it will not consider te given "id" for sorting.
+// The given column should be one of the columns of the given table, otherwise
+// NewPaginator will return an error.
 func OrderByDesc(column string) Option {
 	return func(p *paginator) error {
 		p.orderByClauses = append(p.orderByClauses, orderByClause{
@@ -71,25 +323,423 @@ func OrderByDesc(column string) Option {
 	}
 }
 
+// DefaultOrderBy is an option for NewPaginator that allows you to sort the records by
+// the given column and direction ("ASC" or "DESC") when the request does not have sort
+// directives, e.g. to show the latest records first by default. Unlike OrderByAsc and
+// OrderByDesc, which always apply, the sort directives of the request will override
+// the default sorting. Repeat the option to sort by multiple columns by default. The
+// records will still be sorted by the "id" at the end, so given the option:
+//
+//	DefaultOrderBy("created_at", "DESC")
+//
+// and a request url without the ``sort`` parameter, the records will be sorted with
+// "ORDER BY created_at DESC,id", while a request url with ``sort=+name`` will sort the
+// records with "ORDER BY name ASC,id". The given column should be one of the columns
+// of the given table, otherwise NewPaginator will return an error.
+func DefaultOrderBy(column, direction string) Option {
+	return func(p *paginator) error {
+		direction = strings.ToUpper(strings.TrimSpace(direction))
+		if direction != "ASC" && direction != "DESC" {
+			return fmt.Errorf("paginate: invalid default sorting direction %q; should be ASC or DESC", direction)
+		}
+		p.defaultOrderByClauses = append(p.defaultOrderByClauses, orderByClause{
+			column:  column,
+			sorting: direction,
+		})
+		return nil
+	}
+}
+
+// OrderByCase is an option for NewPaginator that allows you to sort the records
+// following a custom order of the values of the given column. Records whose column
+// value is equal to the first given value will come first, followed by the records
+// matching the second value, and so on. Records that do not match any of the given
+// values will come last. The given values will be bound as arguments of the sql
+// command generated by Paginator.Paginate.
+func OrderByCase(column string, values ...interface{}) Option {
+	return func(p *paginator) error {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return fmt.Errorf("paginate: order by case column should not be an empty string")
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("paginate: order by case requires at least one value")
+		}
+		p.orderByClauses = append(p.orderByClauses, orderByClause{
+			column:     createCaseExpression(column, len(values)),
+			sorting:    "ASC",
+			args:       values,
+			expression: true,
+			caseColumn: column,
+		})
+		return nil
+	}
+}
+
+// Search is an option for NewPaginator that allows you to search the records of the
+// table with the terms given in the request parameter ``param``. Records will match
+// when any of the given columns contains any of the search terms, so given the option
+// Search("q", "name", "last_name") and a request url like:
+//
+//	http://localhost/employees?q=smith&q=gates
+//
+// Paginator will match the employees whose name or last name contains "smith" or
+// "gates". The search is case-insensitive for postgres (ILIKE). For mysql the case
+// sensitivity depends on the collation of the columns (LIKE).
+func Search(param string, columns ...string) Option {
+	return func(p *paginator) error {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			return fmt.Errorf("paginate: search parameter should not be an empty string")
+		}
+		if len(columns) == 0 {
+			return fmt.Errorf("paginate: search requires at least one column")
+		}
+		p.searchParam = param
+		p.searchColumns = columns
+		return nil
+	}
+}
+
+// LastModifiedColumn is an option for NewPaginator which indicates the name of the
+// timestamp column that Paginator will use to compute the last modification time
+// of the paginated data. See Paginator.LastModified. By default Paginator will
+// use the column "updated_at".
+func LastModifiedColumn(column string) Option {
+	return func(p *paginator) error {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return fmt.Errorf("paginate: last modified column should not be an empty string")
+		}
+		p.lastModifiedColumn = column
+		return nil
+	}
+}
+
+// SoftDelete is an option for NewPaginator which indicates the name of the nullable
+// column, e.g. "deleted_at", that marks the soft deleted records of the table. Paginator
+// will filter out these records adding the sql clause ``deleted_at IS NULL`` to the
+// where clauses of the sql command, unless the IncludeDeleted option is given. Like
+// the rest of the columns, the column is qualified with the name of the table when
+// there are join clauses and quoted with the QuoteIdentifiers option.
+func SoftDelete(column string) Option {
+	return func(p *paginator) error {
+		column = strings.TrimSpace(column)
+		if !columnNameRegexp.MatchString(column) {
+			return fmt.Errorf("paginate: invalid soft delete column %q", column)
+		}
+		p.softDeleteColumn = column
+		return nil
+	}
+}
+
+// IncludeDeleted is an option for NewPaginator that makes Paginator include the soft
+// deleted records in the pagination, e.g. for admin views. See the SoftDelete option.
+func IncludeDeleted() Option {
+	return func(p *paginator) error {
+		p.includeDeleted = true
+		return nil
+	}
+}
+
+// OrderByAscNullsFirst is like OrderByAsc but sorts the NULL values of the given
+// column first, e.g. "salary ASC NULLS FIRST". Since mysql and mariadb do not support
+// NULLS FIRST and NULLS LAST, the clause is emulated with "salary IS NULL DESC,salary ASC".
+func OrderByAscNullsFirst(column string) Option {
+	return orderByWithNulls(column, "ASC", "FIRST")
+}
+
+// OrderByAscNullsLast is like OrderByAsc but sorts the NULL values of the given
+// column last, e.g. "salary ASC NULLS LAST". See OrderByAscNullsFirst.
+func OrderByAscNullsLast(column string) Option {
+	return orderByWithNulls(column, "ASC", "LAST")
+}
+
+// OrderByDescNullsFirst is like OrderByDesc but sorts the NULL values of the given
+// column first, e.g. "salary DESC NULLS FIRST". See OrderByAscNullsFirst.
+func OrderByDescNullsFirst(column string) Option {
+	return orderByWithNulls(column, "DESC", "FIRST")
+}
+
+// OrderByDescNullsLast is like OrderByDesc but sorts the NULL values of the given
+// column last, e.g. "salary DESC NULLS LAST". See OrderByAscNullsFirst.
+func OrderByDescNullsLast(column string) Option {
+	return orderByWithNulls(column, "DESC", "LAST")
+}
+
+// orderByWithNulls returns an option that adds a custom ORDER BY clause with the
+// given sorting direction ("ASC" or "DESC") and NULLS placement ("FIRST" or "LAST").
+func orderByWithNulls(column, sorting, nulls string) Option {
+	return func(p *paginator) error {
+		p.orderByClauses = append(p.orderByClauses, orderByClause{
+			column:  column,
+			sorting: sorting,
+			nulls:   nulls,
+		})
+		return nil
+	}
+}
+
+// NullsLastByDefault is an option for NewPaginator that makes Paginator sort the NULL
+// values of the nullable columns of the table last, regardless of the sorting direction.
+// Nullable columns are the ones whose struct fields have any of the nullable types
+// provided by this package (e.g. NullString) or a pointer type (e.g. *string). Use
+// NullsFirst to override this behavior for specific columns.
+func NullsLastByDefault() Option {
+	return func(p *paginator) error {
+		p.nullsLastByDefault = true
+		return nil
+	}
+}
+
+// NullsFirst is an option for NewPaginator that makes Paginator sort the NULL values
+// of the given nullable columns first, regardless of the sorting direction.
+func NullsFirst(columns ...string) Option {
+	return func(p *paginator) error {
+		p.nullsFirst = append(p.nullsFirst, columns...)
+		return nil
+	}
+}
+
+// OrderBySeededRandom is an option for NewPaginator that allows you to sort the
+// records in a pseudo-random order determined by the given seed. The order is
+// computed from a hash of the id column and the seed, so the same seed will always
+// give the same order and the pagination will be stable across pages. This is
+// useful, for example, to get reproducible samples of records per user.
+func OrderBySeededRandom(seed int64) Option {
+	return func(p *paginator) error {
+		p.randomSeed = seed
+		p.seededRandom = true
+		return nil
+	}
+}
+
+// FullTextSearch is an option for NewPaginator that allows you to make a ranked
+// postgres full-text search of the query given in the request parameter ``param``.
+// The weighted parameter maps the columns that will be searched with their postgres
+// weights: "A", "B", "C" or "D", from the highest to the lowest. Records will be
+// sorted by their rank (ts_rank) and then by id. So given the option:
+//
+//	FullTextSearch("q", map[string]string{"name": "A", "notes": "B"})
+//
+// and a request url like http://localhost/employees?q=golang, records mentioning
+// "golang" in their name will come before the ones mentioning it only in their notes.
+// The query is parsed with websearch_to_tsquery, which requires postgres 11 or newer.
+func FullTextSearch(param string, weighted map[string]string) Option {
+	return func(p *paginator) error {
+		if p.dialect != "postgres" {
+			return fmt.Errorf("paginate: full-text search is only supported for postgres")
+		}
+		param = strings.TrimSpace(param)
+		if param == "" {
+			return fmt.Errorf("paginate: full-text search parameter should not be an empty string")
+		}
+		if len(weighted) == 0 {
+			return fmt.Errorf("paginate: full-text search requires at least one column")
+		}
+		weights := make(map[string]string, len(weighted))
+		for column, weight := range weighted {
+			switch weight {
+			case "A", "B", "C", "D":
+				weights[column] = weight
+			default:
+				return fmt.Errorf("paginate: invalid full-text search weight %q for column %q", weight, column)
+			}
+		}
+		p.fullTextSearchParam = param
+		p.fullTextSearchWeights = weights
+		return nil
+	}
+}
+
+// PlainFullTextSearch is an option for NewPaginator that allows you to make a postgres
+// full-text search of the query given in the request parameter ``param`` in a single
+// column. It works like FullTextSearch with the column as the only weighted column,
+// but the query is parsed with plainto_tsquery instead, so it is available before
+// postgres 11 and every word of the query, and of repeated parameters, should match.
+// So given the option:
+//
+//	PlainFullTextSearch("notes", "q")
+//
+// and a request url like http://localhost/employees?q=golang+developer, the predicate
+// ``(setweight(to_tsvector(coalesce(notes::text, '')), 'A')) @@ plainto_tsquery($1)``
+// will be added to the where clauses with the query "golang developer" as argument.
+func PlainFullTextSearch(column, param string) Option {
+	return func(p *paginator) error {
+		if err := FullTextSearch(param, map[string]string{strings.TrimSpace(column): "A"})(p); err != nil {
+			return err
+		}
+		p.fullTextSearchPlain = true
+		return nil
+	}
+}
+
+// StrictPaging is an option for NewPaginator that makes NewPaginator return an error
+// when the ``page`` parameter of the request is not a number greater than zero, e.g.
+// ``page=0``, ``page=-3`` or ``page=abc``. By default Paginator will use the first
+// page in these cases.
+//
+// With StrictPaging NewPaginator will also return an error when the query string of
+// the request is malformed, e.g. ``?page=2?name=ringo``, or when the ``page`` or
+// ``page_size`` parameters are given more than once. By default Paginator will
+// normalize the query string and use the first value of these parameters.
+//
+// Together with the MaxOffset option, StrictPaging will also make NewPaginator and
+// Paginator.SetPage return ErrOffsetTooDeep for the pages beyond the max offset.
+func StrictPaging() Option {
+	return func(p *paginator) error {
+		p.strictPaging = true
+		return nil
+	}
+}
+
+// StrictRawClauses is an option for NewPaginator that makes Paginator.AddWhereClause reject
+// the RawWhereClause predicates referencing columns that are not columns of the table, e.g.
+// a ``password_hash`` column. The columns of the given tables, e.g. joined tables, can be
+// referenced when they are qualified with the table name, e.g. ``manager.name = ?``. The
+// identifiers of the predicates are found with a best-effort tokenization, not a full sql
+// parser, so sql keywords and the names of the sql functions are allowed, string literals
+// are ignored, and sub-queries, sql comments and backslashes are rejected. Like any raw
+// where clause, the predicates are parenthesized, so an OR cannot bypass the rest of the
+// where clauses, e.g. the ones filtering the records of a tenant.
+func StrictRawClauses(tables ...string) Option {
+	return func(p *paginator) error {
+		for _, table := range tables {
+			if !sqlFunctionRegexp.MatchString(table) {
+				return fmt.Errorf("paginate: invalid table name %q", table)
+			}
+		}
+		p.strictRawClauses = true
+		p.rawClauseTables = tables
+		return nil
+	}
+}
+
+// PlaceholderStyle is an option for NewPaginator that changes the style of the placeholders
+// of the sql commands created by Paginator, e.g. for database drivers that expect a style
+// other than the one of the given dialect. The available styles are "?" for question marks,
+// "$" for $1, $2, etc., "@p" for @p1, @p2, etc. and ":" for :1, :2, etc. By default
+// Paginator will use "$" for postgres and "?" for mysql and mariadb.
+func PlaceholderStyle(style string) Option {
+	return func(p *paginator) error {
+		s, ok := placeholderStyles[style]
+		if !ok {
+			return fmt.Errorf("paginate: invalid placeholder style %q", style)
+		}
+		p.placeholders = s
+		return nil
+	}
+}
+
+// QuoteIdentifiers is an option for NewPaginator that makes Paginator quote the names of the
+// table and its columns in the sql commands, with backticks for mysql and mariadb and with
+// double quotes for postgres, e.g. for columns named after reserved words like ``order``:
+//
+//	SELECT "id", "order", count(*) over() FROM "employees" ORDER BY "id" LIMIT 30 OFFSET 0
+//
+// The table and the columns that are selected, filtered, sorted or grouped are quoted, as well as
+// the tables and columns of the InnerJoin, LeftJoin and RightJoin clauses; the raw join clauses,
+// the raw where clauses and the custom "ORDER BY" expressions are used as given.
+func QuoteIdentifiers() Option {
+	return func(p *paginator) error {
+		p.quoteIdentifiers = true
+		return nil
+	}
+}
+
+// OnQuery is an option for NewPaginator that makes Paginator call the given hook with
+// every sql command that it runs with a Querier, i.e. in Execute, ForEach, IDs and Count,
+// including the separate count queries. The hook is given the sql command, its arguments
+// and the time it took to run it, e.g. to log the slow queries or to trace them. The hook
+// is called even if the query failed. A nil hook is ignored.
+//
+// The elapsed time only covers the call to QueryContext, i.e. until the database starts
+// returning the rows, not the scanning of the rows. With the ConcurrentCount option the
+// hook is called from two goroutines at the same time, so it should be safe for
+// concurrent use.
+func OnQuery(hook func(sql string, args []interface{}, elapsed time.Duration)) Option {
+	return func(p *paginator) error {
+		p.onQuery = hook
+		return nil
+	}
+}
+
+// EmptyAsNull is an option for NewPaginator that makes Paginator interpret a filter
+// parameter with the equal sign (=) and an empty value as the IS NULL sql clause, so for
+// example given a request url like:
+//
+//	http://localhost/employees?null_date=
+//
+// the records will be filtered with the sql clause ``null_date IS NULL``. By default
+// Paginator will ignore the parameters with empty values.
+func EmptyAsNull() Option {
+	return func(p *paginator) error {
+		p.emptyAsNull = true
+		return nil
+	}
+}
+
+// KeysetPagination is an option for NewPaginator that makes Paginator paginate the
+// records with a continuation token given in the request parameter with the given
+// name instead of with the ``page`` parameter.
+//
+// The continuation token encodes the values of all the "ORDER BY" columns of the
+// last record of a page, and Paginator will use them to fetch the records that come
+// after that record, e.g. for ``sort=-salary,+name`` the query will have the
+// "where" clause:
+//
+//  (salary < $1 OR (salary = $2 AND name > $3) OR (salary = $4 AND name = $5 AND id > $6))
+//
+// The NULL values of the nullable columns are matched with IS NULL predicates that
+// follow where the NULL values are sorted, see the NullsFirst option.
+//
+// The token of the next page is returned in PaginationResponse.NextCursor. Note that
+// PaginationResponse.TotalSize will hold the number of records left from the given
+// continuation token onwards.
+//
+// KeysetPagination cannot be combined with "ORDER BY" expressions like the ones of
+// OrderByCase, OrderBySeededRandom, FullTextSearch or PlainFullTextSearch.
+func KeysetPagination(param string) Option {
+	return func(p *paginator) error {
+		if param == "" {
+			return fmt.Errorf("paginate: keyset pagination parameter should not be empty")
+		}
+		p.cursorParam = param
+		return nil
+	}
+}
+
 // NewPaginator creates a Paginator object ready to paginate data from a database table.
 //
 // The table parameter should be a struct object with fields representing the target
 // database table you want to paginate. The dialect parameter should be a string
-// representing the sql dialect you are using "postgres" or "mysql", for example.
+// representing the sql dialect you are using "postgres", "mysql" or "mariadb", for example.
 // For available options you can pass to Paginator check: TableName and PageSize.
 //
 // When the PageSize option is not given paginator will try to get the page size from the
 // request parameter ``page_size``. If there is no ``page_size`` parameter NewPaginator
 // will set the Paginator with the default page size which is 30. When the TableName option
 // is not given, NewPaginator will infer the database table name from the table argument
-// given, so it will extract the name from the struct variable.
+// given, so it will extract the name from the struct variable. Use the PageParam and
+// PageSizeParam options to change the names of the ``page`` and ``page_size`` parameters.
 func NewPaginator(table interface{}, dialect string, u url.URL, opts ...Option) (Paginator, error) {
 	err := dialectPlaceholder.CheckIfDialectIsSupported(dialect)
 	if err != nil {
 		return nil, err
 	}
 
-	p := &paginator{table: table, rv: reflect.ValueOf(table), dialect: dialect}
+	p := &paginator{
+		table:              table,
+		rv:                 reflect.ValueOf(table),
+		dialect:            dialect,
+		lastModifiedColumn: defaultLastModifiedColumn,
+		filterConjunction:  _and,
+		separateCount:      dialect == "mariadb",
+		placeholders:       dialectPlaceholder.GetPlaceholderStyle(dialect),
+		pageParam:          defaultPageParam,
+		pageSizeParam:      defaultPageSizeParam,
+		sortParam:          defaultSortParam,
+	}
 
 	// Let's try to set the options if any.
 	for _, opt := range opts {
@@ -105,8 +755,29 @@ func NewPaginator(table interface{}, dialect string, u url.URL, opts ...Option)
 		p.getTableName()
 	}
 
+	paginationParams := []string{p.pageParam, p.pageSizeParam}
+	if p.limitOffset {
+		paginationParams = append(paginationParams, defaultLimitParam, defaultOffsetParam)
+	}
+	u, err = normalizeURL(u, p.strictPaging, paginationParams...)
+	if err != nil {
+		return nil, err
+	}
+
 	v := u.Query()
-	requestParameters := getRequestData(v)
+	requestParameters := getRequestData(v, p.pageParam, p.pageSizeParam)
+
+	if p.strictPaging {
+		if err := validatePageNumber(v, p.pageParam); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.limitOffset {
+		if err := p.applyLimitOffset(v, &requestParameters); err != nil {
+			return nil, err
+		}
+	}
 
 	// Let's try to set the pageSize if it has not been set yet.
 	// We will try to get this value from the request.
@@ -116,6 +787,20 @@ func NewPaginator(table interface{}, dialect string, u url.URL, opts ...Option)
 
 	p.pageNumber = requestParameters.pageNumber
 
+	if p.maxPageSize > 0 && p.pageSize > p.maxPageSize {
+		p.pageSize = p.maxPageSize
+		p.pageSizeClamped = true
+	}
+
+	// The page number of an offset given directly in the request
+	// is the page where the first record of the offset is.
+	if p.hasOffset {
+		p.pageNumber = p.offset/p.pageSize + 1
+	}
+	if err := p.clampPageNumber(); err != nil {
+		return nil, err
+	}
+
 	// Order matters. Validation should happen before getting
 	// all the data to initialize the Paginator.
 	if err := p.validateTable(); err != nil {
@@ -123,16 +808,118 @@ func NewPaginator(table interface{}, dialect string, u url.URL, opts ...Option)
 	}
 
 	// Now let's get all the data that our paginator requires.
-	// Order matters: getCols func should be called before
-	// getParameters func.
-	p.getID()
-	p.getColsAndMapParameters()
-	p.getFieldNames()
-	p.getFilters()
-	p.parameters = getParameters(p.cols, p.filters, p.mappers, u)
+	// Order matters: the columns should be loaded before
+	// calling getParameters.
+	p.loadTableMetadata()
+	if err := p.validateColumnNames(); err != nil {
+		return p, err
+	}
+	if err := p.excludeColumns(); err != nil {
+		return p, err
+	}
+	if err := p.validateOrderByClauses(); err != nil {
+		return p, err
+	}
+	if err := p.validateGroupBy(); err != nil {
+		return p, err
+	}
+	if p.fieldsParam != "" {
+		fields, excluded, err := parseFieldsParam(v.Get(p.fieldsParam))
+		if err != nil {
+			return p, err
+		}
+		p.requestFields, p.requestExcluded = fields, excluded
+	}
+	if err := p.getSelectedColumns(); err != nil {
+		return p, err
+	}
+	p.parameters = getParameters(p.cols, p.filters, p.rangeColumns(), p.nullableColumns(), p.mappers, u, p.sortParam, p.emptyAsNull)
+
+	if len(p.jsonColumns) > 0 {
+		if p.dialect != "postgres" {
+			return p, fmt.Errorf("paginate: json filters are only supported for postgres")
+		}
+		p.parameters = append(p.parameters, getJSONParameters(p.jsonColumns, p.mappers, u)...)
+	}
+
+	if len(p.arrayColumns) > 0 {
+		if p.dialect != "postgres" {
+			return p, fmt.Errorf("paginate: array filters are only supported for postgres")
+		}
+		setArraySigns(p.parameters, p.arrayColumns)
+	}
+
+	if p.softDeleteColumn != "" && !p.includeDeleted {
+		deleted := p.softDeleteColumn
+		p.predicates = append(p.predicates, RawWhereClause{
+			predicate: deleted + " " + _isnull,
+			dialect:   p.dialect,
+			render: func(column func(string) string) string {
+				return column(deleted) + " " + _isnull
+			},
+		})
+	}
+
+	if p.searchParam != "" {
+		if err := p.addSearchClause(v); err != nil {
+			return p, err
+		}
+	}
 
 	// Let's clean our orderByClauses slice.
-	p.orderByClauses.Clean(p.id)
+	p.orderByClauses.Clean(p.ids...)
+
+	if p.fullTextSearchParam != "" {
+		if err := p.addFullTextSearchClauses(v); err != nil {
+			return p, err
+		}
+	}
+
+	if p.seededRandom {
+		p.orderByClauses = append(p.orderByClauses, createSeededRandomOrderByClause(p.dialect, p.id, p.randomSeed))
+	}
+
+	// The default "ORDER BY" clauses take the place of
+	// the sort directives when the request has none.
+	if len(getSortClauses(p.parameters, p.sortParam, p.cols)) == 0 {
+		p.defaultOrderByClauses.Clean(p.ids...)
+		p.orderByClauses = append(p.orderByClauses, p.defaultOrderByClauses...)
+	}
+
+	if p.cursorParam != "" {
+		if err := p.addKeysetClause(v); err != nil {
+			return p, err
+		}
+	}
 
 	return p, nil
 }
+
+// NewPaginatorFromRequest creates a Paginator object ready to paginate data from a
+// database table with the url of the given http.Request. It is a convenience wrapper
+// around NewPaginator for http handlers, so check NewPaginator for more information.
+func NewPaginatorFromRequest(table interface{}, dialect string, r *http.Request, opts ...Option) (Paginator, error) {
+	if r == nil || r.URL == nil {
+		return nil, fmt.Errorf("paginate: the given request should have a non-nil url")
+	}
+	return NewPaginator(table, dialect, *r.URL, opts...)
+}
+
+// ValidateTable checks that the given table struct can be paginated without creating
+// a Paginator from a request, e.g. in unit tests or when an application starts. It
+// returns the same errors as NewPaginator for the given table, e.g. when no field is
+// tagged with "id" or a field has an unsupported type. Additionally, ValidateTable
+// returns an error for the tags that NewPaginator ignores silently, i.e. unknown or
+// malformed tags and invalid "param" names, and when two fields are mapped to the
+// same column or the same request parameter.
+func ValidateTable(table interface{}) error {
+	if table == nil {
+		return fmt.Errorf("paginate: table should be of struct type")
+	}
+	p := &paginator{table: table, rv: reflect.ValueOf(table)}
+	if err := p.validateTable(); err != nil {
+		return err
+	}
+	p.loadTableMetadata()
+	return p.validateTags()
+}
@@ -19,6 +19,15 @@ Use these with the parameters in the request url:
 	gte = ">="
 	lte = "<="
 	ne  = "<>"
+	contains = "=~"
+	notcontains = "=!~"
+
+The contains operator matches the records whose column value contains the given value,
+so for example ``name=~ring`` will produce the sql clause ``name ILIKE $1`` for postgres
+(``name LIKE ?`` for mysql) with the argument ``%ring%``. The wildcard characters % and _
+given in the value will be matched literally. Likewise, the notcontains operator matches
+the records whose column value does not contain the given value, so for example
+``name=!~ring`` will produce the sql clause ``name NOT ILIKE $1`` for postgres.
 
 
 For ordering records based on column names use the following syntax in the url with the ``sort``
@@ -27,6 +36,10 @@ order use the minus (-) sign:
 
 	http://localhost/employees?name=rob&sort=+name,-age
 
+Paginator will always sort the records by the id of the table last, e.g. ``ORDER BY name ASC,age DESC,id``,
+so the order of the records is deterministic between pages. The id can be sorted explicitly too, e.g.
+``sort=-id`` will produce the sql clause ``ORDER BY id DESC``.
+
 
 When parameters with the equal sign (=) in the request url are repeated, Paginator will
 interpret this as an IN sql clause. So for example given a database table ``Employees``
@@ -48,6 +61,36 @@ Paginator will produce an sql query similar to this:
 
 	SELECT id, name FROM employees WHERE name NOT IN($1,$2) ORDER BY id LIMIT 30 OFFSET 0
 
+The values ``null`` and ``notnull`` of a parameter with the equal sign (=) will be interpreted
+as the IS NULL and IS NOT NULL sql clauses respectively, so for example ``null_date=null`` will
+produce the sql clause ``null_date IS NULL``. This only applies to the nullable columns, i.e. the
+fields with a nullable type like NullTime or a pointer type; for other columns the values are
+matched literally. To match the literal strings instead, quote them with
+double quotes, e.g. ``name="null"`` will produce ``name = $1`` with the argument "null". Parameters
+with empty values, e.g. ``null_date=``, are ignored by default; with the EmptyAsNull option they will
+be interpreted as the IS NULL sql clause as well.
+
+When a parameter with the equal sign (=) has a range value with two dots separating the lower
+and upper bounds, Paginator will interpret this as a BETWEEN sql clause, as long as the column
+is numeric or a time, since a value like ``email=a..b@x.com`` is a valid string. So for example,
+given a request url like:
+
+	http://localhost/employees?salary=4000..8000
+
+Paginator will produce an sql query similar to this:
+
+	SELECT id, name, salary FROM employees WHERE salary BETWEEN $1 AND $2 ORDER BY id LIMIT 30 OFFSET 0
+
+With the Search option Paginator will match search terms given in a request parameter
+against multiple columns. Repeated search terms will match records containing any of them.
+So for example given the option Search("q", "name", "last_name") and a request url like:
+
+	http://localhost/employees?q=smith&q=gates
+
+Paginator will produce an sql query similar to this when using postgres:
+
+	SELECT id, name, last_name FROM employees WHERE (name ILIKE ANY(ARRAY[$1,$2]) OR last_name ILIKE ANY(ARRAY[$3,$4])) ORDER BY id LIMIT 30 OFFSET 0
+
 Example of the table struct field tags and their meanings
 (use a ; to specify multiple tags at the same time):
 
@@ -77,11 +120,47 @@ Example of the table struct field tags and their meanings
 	// the column "id".
 	ID int `paginate:"col=id;param=person_id"`
 
+	// When joining other tables with Paginator.AddJoinClause, the columns of the
+	// paginated table will be qualified with the table name (e.g. "employees.id")
+	// to avoid clashes with the columns of the joined tables. Use the tag "col" with
+	// a qualified column name to select a column from a joined table. By default,
+	// a qualified column will be mapped with a request parameter without the table
+	// name, so in this case the request parameter would be "programming_language".
+	ProgrammingLanguage string `paginate:"filter;col=developer.programming_language"`
+
+	// Use the tag "fn" to apply an sql function to a column when filtering it.
+	// So, for example, in this case a request parameter "date_joined=2021-03-01"
+	// will filter the records with DATE(date_joined) = $1, matching all the records
+	// joined on that day regardless of the time.
+	DateJoined time.Time `paginate:"filter;fn=DATE"`
+
+	// Use the tag "json" with the tag "filter" to filter a jsonb column by the keys
+	// of its json values (postgres only). So, for example, in this case a request
+	// parameter "meta.role=admin" will filter the records with meta->>'role' = $1,
+	// and a nested key like "meta.team.name=core" will filter the records with
+	// meta->'team'->>'name' = $1. The values of the keys are compared as text.
+	Meta string `paginate:"filter;json"`
+
+	// Use the tag "array" with the tag "filter" to filter an array column by the
+	// elements of its values (postgres only). So, for example, in this case a request
+	// parameter "tags=go" will filter the records with $1 = ANY(tags), and repeated
+	// parameters like "tags=go&tags=rust" will filter the records whose arrays contain
+	// any of the values with tags && ARRAY[$1,$2].
+	Tags string `paginate:"filter;array"`
+
+	// Use the tag "noselect" to filter or sort the records by a column that should
+	// not be returned, e.g. a private organization id. So, for example, in this case
+	// a request parameter "org_id=3" will filter the records with org_id = $1, but the
+	// column will not be selected and Scan will leave the field with its zero value.
+	OrgID int `paginate:"filter;noselect;col=org_id"`
+
 	// The tag "id" is required. If it is not given, Paginator cannot be instantiated
 	// and it will return an error. The tag "id" allows Paginator to keep the same order
 	// between pages and results. In simple words, it will make the pagination deterministic.
 	// Usually, the column used with this tag should be the primary key or unique identifier
-	// of the given table.
+	// of the given table. For tables with a composite primary key, e.g. (tenant_id, item_id),
+	// tag all the fields of the key with "id" and Paginator will sort the records by all of
+	// them in declaration order, e.g. ``ORDER BY tenant_id,item_id``.
 	ID int `paginate:"id"`
 
 NOTES:
@@ -1,6 +1,6 @@
 module github.com/ottotech/paginate
 
-go 1.15
+go 1.18
 
 require (
 	github.com/go-sql-driver/mysql v1.5.0
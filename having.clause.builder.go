@@ -0,0 +1,61 @@
+package paginate
+
+// NewRawHavingClause will give you a validated instance of a RawHavingClause object.
+//
+// Use this constructor whenever you want to filter the groups created with
+// the GroupBy option with a custom sql "having" clause.
+//
+// Example of how to use the returned RawHavingClause object:
+//
+//  ...
+//  paginator, _ := NewPaginator(MyTable{}, "mysql", *url, GroupBy("department"))
+//  rawHavingSql, err := NewRawHavingClause("mysql")
+//  if err != nil {
+//     // Handle error gracefully.
+//  }
+//
+//  rawHavingSql.AddPredicate("count(*) > ?")
+//  rawHavingSql.AddArg(5)
+//
+//  err = paginator.AddHavingClause(rawHavingSql)
+//  if err != nil {
+//     // Handle error gracefully.
+//  }
+//
+func NewRawHavingClause(dialect string) (RawHavingClause, error) {
+	if err := dialectPlaceholder.CheckIfDialectIsSupported(dialect); err != nil {
+		return RawHavingClause{}, err
+	}
+
+	return RawHavingClause{
+		dialect: dialect,
+	}, nil
+}
+
+type RawHavingClause struct {
+	predicate string
+	args      []interface{}
+	dialect   string
+}
+
+// String returns the RawHavingClause predicate without arguments as string. The
+// placeholders are rendered with the style of the dialect, e.g. "count(*) > $1"
+// for postgres.
+func (raw RawHavingClause) String() string {
+	return renderPlaceholders(raw.predicate, raw.dialect, dialectPlaceholder.GetPlaceholderStyle(raw.dialect))
+}
+
+// AddPredicate adds the given predicate to a RawHavingClause instance.
+// If your sql "having" clause requires multiple arguments use the
+// question mark symbol "?" as placeholders, later use AddArg to
+// add the arguments you need.
+func (raw *RawHavingClause) AddPredicate(predicate string) {
+	raw.predicate = predicate
+}
+
+// AddArg adds the given argument to the RawHavingClause instance.
+// If your custom raw sql having clause requires more than one argument
+// you should call AddArg multiple times.
+func (raw *RawHavingClause) AddArg(v interface{}) {
+	raw.args = append(raw.args, v)
+}
@@ -1,16 +1,28 @@
 package paginate
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 )
 
-func getParameters(colNames, filters []string, mappers mappers, u url.URL) parameters {
+func getParameters(colNames, filters, rangeCols, nullableCols []string, mappers mappers, u url.URL, sortParam string, emptyAsNull bool) parameters {
 	list := make(parameters, 0)
-	decodedURL, _ := url.PathUnescape(u.String())
+	decodedURL, err := url.PathUnescape(u.String())
+	if err != nil {
+		// Let's not lose all the parameters because of
+		// a single malformed percent-encoded value.
+		decodedURL = u.String()
+	}
 
 	getParameter := func(key, val, char string) (bool, parameter) {
 		p := parameter{}
@@ -92,7 +104,48 @@ func getParameters(colNames, filters []string, mappers mappers, u url.URL) param
 				list = append(list, newP)
 				continue
 			}
+			if ok, newP := getParameter(key, value, notcontains); ok {
+				newP.sign = _notlike
+				list = append(list, newP)
+				continue
+			}
+			if ok, newP := getParameter(key, value, contains); ok {
+				newP.sign = _like
+				list = append(list, newP)
+				continue
+			}
+			// An empty value like ``null_date=`` will be ignored, unless
+			// emptyAsNull is true, in which case it will be used to build
+			// an sql IS NULL clause. See the EmptyAsNull option.
+			if value == eq {
+				newP := parameter{name: key, sign: _isnull}
+				if emptyAsNull && !isParameterIn(newP, list) {
+					list = append(list, newP)
+				}
+				continue
+			}
 			if ok, newP := getParameter(key, value, eq); ok {
+				// The values ``null`` and ``notnull`` of the nullableCols will be used
+				// to build an sql IS NULL or IS NOT NULL clause without arguments. Quoted
+				// values, e.g. ``name="null"``, will match the literal strings instead.
+				nullable := isStringIn(key, nullableCols)
+				switch {
+				case newP.value == "null" && nullable:
+					newP.sign = _isnull
+				case newP.value == "notnull" && nullable:
+					newP.sign = _isnotnull
+				case newP.value == `"null"`, newP.value == `"notnull"`:
+					newP.value = strings.Trim(newP.value, `"`)
+				}
+				// A range value like ``4000..8000`` of the rangeCols will be used
+				// to build an sql BETWEEN clause. Malformed ranges will be skipped.
+				if strings.Contains(newP.value, rangesep) && isStringIn(key, rangeCols) {
+					bounds := strings.Split(newP.value, rangesep)
+					if len(bounds) != 2 || bounds[0] == "" || bounds[1] == "" {
+						continue
+					}
+					newP.sign = _between
+				}
 				list = append(list, newP)
 				continue
 			}
@@ -190,7 +243,7 @@ func getParameters(colNames, filters []string, mappers mappers, u url.URL) param
 	}
 
 	// As an special case we need to also get our custom sort parameter.
-	sort := "sort"
+	sort := sortParam
 	for _, p := range params {
 		if len(p) <= len(sort) {
 			continue
@@ -208,9 +261,107 @@ func getParameters(colNames, filters []string, mappers mappers, u url.URL) param
 	return list
 }
 
-func getRequestData(v url.Values) paginationRequest {
+// jsonKeyRegexp matches the valid keys of the json values of the json columns
+// given in the request url, e.g. "role" or "address.city" for nested keys.
+var jsonKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+
+// getJSONParameters gets the parameters of the keys of the given json columns
+// given in the request url, e.g. ``meta.role=admin`` for the json column "meta".
+// The names of the parameters will be the postgres expressions that extract the
+// keys as text from the columns (see jsonPath), so ``meta.role=admin`` will give
+// the parameter "meta->>'role' = admin". Keys that are not valid will be ignored.
+func getJSONParameters(jsonCols []string, colMappers mappers, u url.URL) parameters {
+	list := make(parameters, 0)
+	keys := make([]string, 0)
+	keyMappers := make(mappers, 0)
+	paths := make(map[string]string)
+
+	for _, col := range jsonCols {
+		prefix := col + "."
+		columnIsMapped, customParameterName := colMappers.isColumnMapped(col)
+		if columnIsMapped {
+			prefix = customParameterName + "."
+		}
+
+		for _, rawParam := range strings.Split(u.RawQuery, "&") {
+			requestParam, err := url.QueryUnescape(rawParam)
+			if err != nil {
+				requestParam = rawParam
+			}
+			if !strings.HasPrefix(requestParam, prefix) {
+				continue
+			}
+			key := requestParam[len(prefix):]
+			if i := strings.IndexAny(key, "=<>"); i != -1 {
+				key = key[:i]
+			}
+			name := col + "." + key
+			if !jsonKeyRegexp.MatchString(key) || isStringIn(name, keys) {
+				continue
+			}
+			keys = append(keys, name)
+			paths[name] = jsonPath(col, key)
+			if columnIsMapped {
+				keyMappers.Add(name, prefix+key)
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return list
+	}
+
+	for _, p := range getParameters(keys, keys, nil, keys, keyMappers, u, "", false) {
+		path, ok := paths[p.name]
+		if !ok {
+			continue
+		}
+		p.name = path
+		list = append(list, p)
+	}
+	return list
+}
+
+// jsonPath returns the postgres expression that extracts as text the value of the
+// given key from the given json column, e.g. meta->>'role' for the key "role", or
+// meta->'address'->>'city' for the nested key "address.city".
+func jsonPath(col, key string) string {
+	keys := strings.Split(key, ".")
+	path := col
+	for _, k := range keys[:len(keys)-1] {
+		path += "->'" + k + "'"
+	}
+	return path + "->>'" + keys[len(keys)-1] + "'"
+}
+
+// isJSONPathOf reports whether the given name is a json path of the given
+// column created by jsonPath, e.g. meta->>'role' for the column "meta".
+func isJSONPathOf(name, col string) bool {
+	return strings.HasPrefix(name, col+"->")
+}
+
+// setArraySigns sets the signs of the parameters of the given array columns, so
+// the ``eq`` sign will match the arrays containing the given value and the ``_in``
+// sign will match the arrays containing any of the given values. See _any.
+func setArraySigns(params parameters, arrayCols []string) {
+	for i, p := range params {
+		if !isStringIn(p.name, arrayCols) {
+			continue
+		}
+		switch p.sign {
+		case eq:
+			params[i].sign = _any
+		case _in:
+			params[i].sign = _overlap
+		}
+	}
+}
+
+// getRequestData gets the page number and the page size from the given pageParam
+// and pageSizeParam request parameters, e.g. ``page`` and ``page_size``.
+func getRequestData(v url.Values, pageParam, pageSizeParam string) paginationRequest {
 	p := paginationRequest{}
-	if page := v.Get("page"); page != "" {
+	if page := v.Get(pageParam); page != "" {
 		page, err := strconv.Atoi(page)
 		if err != nil {
 			page = defaultPageNumber
@@ -223,7 +374,7 @@ func getRequestData(v url.Values) paginationRequest {
 		p.pageNumber = defaultPageNumber
 	}
 
-	if pageSize := v.Get("page_size"); pageSize != "" {
+	if pageSize := v.Get(pageSizeParam); pageSize != "" {
 		pageSize, err := strconv.Atoi(pageSize)
 		if err != nil {
 			pageSize = defaultPageSize
@@ -239,24 +390,182 @@ func getRequestData(v url.Values) paginationRequest {
 	return p
 }
 
-func createWhereClause(dialect string, colNames []string, params parameters, extraWhereClauses []RawWhereClause, c chan whereClause) {
+// validatePageNumber returns an error if the given pageParam parameter of the given
+// values is not a number greater than zero. A missing parameter is valid.
+func validatePageNumber(v url.Values, pageParam string) error {
+	page := v.Get(pageParam)
+	if page == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(page)
+	if err != nil {
+		return fmt.Errorf("paginate: %s should be a number; got %q", pageParam, page)
+	}
+	if n <= 0 {
+		return fmt.Errorf("paginate: %s should be greater than zero; got %d", pageParam, n)
+	}
+	return nil
+}
+
+// parseFieldsParam parses the value of the request parameter used to select fields,
+// e.g. ``name,+last_name``, or to exclude fields from the selection, e.g. ``-notes``.
+// It returns an error if the value mixes selected and excluded fields.
+func parseFieldsParam(value string) (fields, excluded []string, err error) {
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		switch {
+		case field == "" || field == "-" || field == "+":
+			continue
+		case strings.HasPrefix(field, "-"):
+			excluded = append(excluded, field[1:])
+		default:
+			fields = append(fields, strings.TrimPrefix(field, "+"))
+		}
+	}
+	if len(fields) > 0 && len(excluded) > 0 {
+		return nil, nil, fmt.Errorf("paginate: cannot select and exclude fields at the same time; got %q", value)
+	}
+	return fields, excluded, nil
+}
+
+// timeLayouts holds the layouts of the timestamps that can be
+// given in the request to filter the time columns.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTimeInLocation parses the given timestamp with any of the timeLayouts.
+// Timestamps without a time zone will be interpreted in the given location.
+func parseTimeInLocation(value string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parameterStartRegexp matches the start of a request parameter, i.e. its name
+// followed by the equal sign or by any other filter operator, e.g. ``salary>``.
+var parameterStartRegexp = regexp.MustCompile(`^[A-Za-z0-9_.\-]+[=<>]`)
+
+// normalizeURL returns the given url with a normalized query string. Question marks
+// in the query string that are followed by a parameter, e.g. ``?page=2?name=ringo``,
+// will be taken as separators of the parameters, while the rest are kept as part of
+// the values, e.g. ``name=why?``. If strict is true an error will be returned instead
+// when the query string is malformed or when any of the given pagination parameters
+// is given more than once.
+func normalizeURL(u url.URL, strict bool, paginationParams ...string) (url.URL, error) {
+	if strict {
+		if _, err := url.ParseQuery(u.RawQuery); err != nil {
+			return u, fmt.Errorf("paginate: malformed query string %q: %v", u.RawQuery, err)
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range u.RawQuery {
+		if r == '?' && parameterStartRegexp.MatchString(u.RawQuery[i+1:]) {
+			if strict {
+				return u, fmt.Errorf("paginate: malformed query string %q: unexpected \"?\"", u.RawQuery)
+			}
+			r = '&'
+		}
+		b.WriteRune(r)
+	}
+	u.RawQuery = b.String()
+
+	if strict {
+		v := u.Query()
+		for _, param := range paginationParams {
+			if len(v[param]) > 1 {
+				return u, fmt.Errorf("paginate: %s should be given only once; got %v", param, v[param])
+			}
+		}
+	}
+
+	return u, nil
+}
+
+// createWhereClause creates the sql "where" clause with the given parameters and extra
+// where clauses. The clauses of the parameters will be joined with the given conjunction
+// ("AND" or "OR"), while the extra where clauses will always be parenthesized and joined
+// with AND, e.g.:
+//
+//	WHERE (name = $1 OR last_name = $2) AND (tenant_id = $3)
+//
+// If any of the columns is mapped to an sql function in the given functions, the
+// function will be applied to the column, e.g. DATE(date_joined) = $1. If any of the
+// columns has a converter in the given converters, the converter will give the
+// arguments of the values of the column given the sign of the parameter, except
+// for the LIKE clauses.
+//
+// If the given table is not empty, e.g. because there are join clauses, the columns
+// will be qualified with it to avoid clashes with the columns of the joined tables,
+// e.g. employees.name = $1, unless they are already qualified with the name of a
+// joined table, e.g. developer.programming_language = $1.
+//
+// If quote is true the columns will be quoted with quoteIdentifier, e.g.
+// "employees"."order" = $1. See the QuoteIdentifiers option.
+//
+// If the clauses of the parameters can never match a record, e.g. because of an
+// inverted range like ``salary=8000..4000``, the returned whereClause will have
+// matchesNothing set to true.
+func createWhereClause(dialect, table string, colNames []string, params parameters, functions map[string]string, converters map[string]func(sign, value string) interface{}, conjunction string, extraWhereClauses []RawWhereClause, quote bool, c chan whereClause) {
 	w := whereClause{}
 	var WHERE = " WHERE "
 	var AND = " AND "
 	var separator string
 	var clauses []string
 	var values []interface{}
+	var nothings int
 
 	for _, name := range colNames {
 		for _, p := range params {
-			if p.name == name {
+			if p.name == name || isJSONPathOf(p.name, name) {
+				// convert gives the argument of the given value of the column.
+				convert := func(v string) interface{} {
+					if converter, ok := converters[name]; ok {
+						return converter(p.sign, v)
+					}
+					return v
+				}
+				column := name
+				if table != "" {
+					column = qualifyColumn(table, column)
+				}
+				if quote {
+					column = quoteIdentifier(dialect, column)
+				}
+				// The json path of a json column is kept after the column, e.g.
+				// meta->>'role'.
+				column += strings.TrimPrefix(p.name, name)
+				if function, ok := functions[name]; ok && p.name == name {
+					column = function + "(" + column + ")"
+				}
+				p.name = column
 				switch p.sign {
 				case _in, _notin:
+					// An empty IN would produce invalid sql, so we will use a predicate
+					// that matches nothing instead. Likewise, an empty NOT IN will use
+					// a predicate that matches everything.
+					if p.value == "" {
+						if p.sign == _in {
+							clauses = append(clauses, "1=0")
+							nothings++
+						} else {
+							clauses = append(clauses, "1=1")
+						}
+						continue
+					}
 					vals := strings.Split(p.value, ",")
 					for _, v := range vals {
-						values = append(values, v)
+						values = append(values, convert(v))
 					}
-					placeholder := dialectPlaceholder.GetPlaceHolder(dialect)
+					placeholder := _placeholder
 					str := ""
 					for i := 0; i < len(vals); i++ {
 						if i == len(vals)-1 {
@@ -266,20 +575,78 @@ func createWhereClause(dialect string, colNames []string, params parameters, ext
 						}
 					}
 					clauses = append(clauses, p.name+" "+p.sign+fmt.Sprintf("(%s)", str))
+				case _any:
+					values = append(values, convert(p.value))
+					clauses = append(
+						clauses,
+						fmt.Sprintf("%s = %s(%s)", _placeholder, _any, p.name),
+					)
+				case _overlap:
+					vals := strings.Split(p.value, ",")
+					placeholders := make([]string, 0, len(vals))
+					for _, v := range vals {
+						values = append(values, convert(v))
+						placeholders = append(placeholders, _placeholder)
+					}
+					clauses = append(
+						clauses,
+						fmt.Sprintf("%s %s ARRAY[%s]", p.name, _overlap, strings.Join(placeholders, ",")),
+					)
+				case _isnull, _isnotnull:
+					clauses = append(clauses, p.name+" "+p.sign)
+				case _like, _notlike:
+					// Wildcards given by users will be matched literally.
+					values = append(values, "%"+escapeLikeValue(p.value)+"%")
+					operator := p.sign
+					if dialect == "postgres" {
+						operator = strings.Replace(operator, _like, "ILIKE", 1)
+					}
+					clauses = append(
+						clauses,
+						fmt.Sprintf("%s %s %s", p.name, operator, _placeholder),
+					)
+				case _between:
+					bounds := strings.Split(p.value, rangesep)
+					lower, upper := convert(bounds[0]), convert(bounds[1])
+					// A BETWEEN clause whose lower bound is greater than its upper
+					// bound matches nothing, whatever the value of the column.
+					if isInvertedRange(lower, upper) {
+						nothings++
+					}
+					values = append(values, lower, upper)
+					placeholder := _placeholder
+					clauses = append(
+						clauses,
+						fmt.Sprintf("%s %s %s AND %s", p.name, _between, placeholder, placeholder),
+					)
 				default:
-					values = append(values, p.value)
+					values = append(values, convert(p.value))
 					clauses = append(
 						clauses,
-						fmt.Sprintf("%s %s %s", p.name, p.sign, dialectPlaceholder.GetPlaceHolder(dialect)),
+						fmt.Sprintf("%s %s %s", p.name, p.sign, _placeholder),
 					)
 				}
 			}
 		}
 	}
 
-	// If there are extra custom where clauses we append them here.
+	// With AND a single clause matching nothing is enough to match nothing,
+	// while with OR all the clauses of the parameters should match nothing.
+	if nothings > 0 && (conjunction != _or || nothings == len(clauses)) {
+		w.matchesNothing = true
+	}
+
+	// If the clauses of the parameters should be combined with OR we
+	// group them together so the extra where clauses are still ANDed.
+	if conjunction == _or && len(clauses) > 1 {
+		clauses = []string{"(" + strings.Join(clauses, " "+_or+" ") + ")"}
+	}
+
+	// If there are extra custom where clauses we append them here. They are
+	// parenthesized, so a predicate with OR, e.g. ``name = ? OR name = ?``,
+	// cannot take precedence over the rest of the clauses.
 	for _, predicate := range extraWhereClauses {
-		clauses = append(clauses, predicate.String())
+		clauses = append(clauses, parenthesize(predicate.predicate))
 		values = append(values, predicate.args...)
 	}
 
@@ -296,108 +663,657 @@ func createWhereClause(dialect string, colNames []string, params parameters, ext
 	c <- w
 }
 
+// parenthesize returns the given sql predicate wrapped in parentheses, unless
+// it is already wrapped as a whole, e.g. ``(a = ? OR b = ?)``.
+func parenthesize(predicate string) string {
+	if strings.HasPrefix(predicate, "(") && strings.HasSuffix(predicate, ")") {
+		depth := 0
+		for i, c := range predicate {
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			// The first parenthesis is closed before the end.
+			if depth == 0 && i < len(predicate)-1 {
+				return "(" + predicate + ")"
+			}
+		}
+		return predicate
+	}
+	return "(" + predicate + ")"
+}
+
+// getTagValue returns the value of the given key in the given
+// struct field tags, e.g. "DATE" for the key "fn" in "fn=DATE".
+func getTagValue(tags []string, key string) (string, bool) {
+	for _, tag := range tags {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if strings.TrimSpace(kv[0]) == key {
+			return strings.TrimSpace(kv[1]), true
+		}
+	}
+	return "", false
+}
+
+// createSearchClause creates a RawWhereClause that matches any of the given search
+// terms against any of the given columns.
+//
+// For postgres we will use the ILIKE ANY(ARRAY[...]) construction which is more
+// efficient than chaining multiple ILIKE predicates with OR, e.g.:
+//
+//	(name ILIKE ANY(ARRAY[$1,$2]) OR last_name ILIKE ANY(ARRAY[$3,$4]))
+//
+// For other dialects every term will be matched with its own LIKE predicate.
+// If quote is true the columns will be quoted, see QuoteIdentifiers.
+func createSearchClause(dialect string, columns, terms []string, quote bool) RawWhereClause {
+	clause := RawWhereClause{dialect: dialect}
+	predicates := make([]string, 0)
+
+	for _, column := range columns {
+		if quote {
+			column = quoteIdentifier(dialect, column)
+		}
+		if dialect == "postgres" {
+			placeholders := strings.TrimSuffix(strings.Repeat("?,", len(terms)), ",")
+			predicates = append(predicates, fmt.Sprintf("%s ILIKE ANY(ARRAY[%s])", column, placeholders))
+			for _, term := range terms {
+				clause.AddArg("%" + escapeLikeValue(term) + "%")
+			}
+			continue
+		}
+		for _, term := range terms {
+			predicates = append(predicates, fmt.Sprintf("%s LIKE ?", column))
+			clause.AddArg("%" + escapeLikeValue(term) + "%")
+		}
+	}
+
+	clause.AddPredicate("(" + strings.Join(predicates, " OR ") + ")")
+	return clause
+}
+
+// createFullTextSearchClauses creates the postgres predicate and "ORDER BY" clause
+// of a full-text search of the given query, parsed with the given tsquery function,
+// in the given weighted columns, e.g. with websearch_to_tsquery:
+//
+//	(setweight(to_tsvector(coalesce(name::text, '')), 'A') || setweight(to_tsvector(coalesce(notes::text, '')), 'B')) @@ websearch_to_tsquery($1)
+//	ts_rank((setweight(...) || setweight(...)), websearch_to_tsquery($2)) DESC
+//
+// The query will be bound as an argument of both clauses. Both clauses are created
+// again with the qualified and quoted columns when the sql command is built.
+func createFullTextSearchClauses(columns []string, weights map[string]string, tsquery, query string) (RawWhereClause, orderByClause) {
+	predicateOf := func(column func(string) string) string {
+		return fmt.Sprintf("%s @@ %s(?)", createFullTextSearchDocument(columns, weights, column), tsquery)
+	}
+	rankOf := func(column func(string) string) string {
+		return fmt.Sprintf("ts_rank(%s, %s(%s))", createFullTextSearchDocument(columns, weights, column), tsquery, _placeholder)
+	}
+	identity := func(column string) string { return column }
+
+	predicate := RawWhereClause{dialect: "postgres", render: predicateOf}
+	predicate.AddPredicate(predicateOf(identity))
+	predicate.AddArg(query)
+
+	rank := orderByClause{
+		column:       rankOf(identity),
+		sorting:      "DESC",
+		args:         []interface{}{query},
+		expression:   true,
+		renderColumn: rankOf,
+	}
+	return predicate, rank
+}
+
+// createFullTextSearchDocument returns the weighted postgres document of the given
+// columns used by createFullTextSearchClauses. Every column is given to column, e.g.
+// to qualify or quote it.
+func createFullTextSearchDocument(columns []string, weights map[string]string, column func(string) string) string {
+	vectors := make([]string, 0, len(columns))
+	for _, c := range columns {
+		vectors = append(vectors, fmt.Sprintf("setweight(to_tsvector(coalesce(%s::text, '')), '%s')", column(c), weights[c]))
+	}
+	return "(" + strings.Join(vectors, " || ") + ")"
+}
+
+// createSeededRandomOrderByClause creates an orderByClause that sorts the records
+// by a hash of the given id column and seed, e.g. for postgres:
+//
+//	md5(id::text || $1) ASC
+//
+// The seed will be bound as an argument of the clause. The expression is created
+// again with the qualified and quoted id column when the sql command is built.
+func createSeededRandomOrderByClause(dialect, id string, seed int64) orderByClause {
+	return orderByClause{
+		column:     createSeededRandomExpression(dialect, id),
+		sorting:    "ASC",
+		args:       []interface{}{strconv.FormatInt(seed, 10)},
+		expression: true,
+		renderColumn: func(column func(string) string) string {
+			return createSeededRandomExpression(dialect, column(id))
+		},
+	}
+}
+
+// createSeededRandomExpression returns the hash expression of the given id
+// column used by createSeededRandomOrderByClause.
+func createSeededRandomExpression(dialect, id string) string {
+	if dialect == "postgres" {
+		return fmt.Sprintf("md5(%s::text || %s)", id, _placeholder)
+	}
+	return fmt.Sprintf("md5(concat(%s, %s))", id, _placeholder)
+}
+
+// sqlLiteral returns the given argument of an sql command of the given dialect as
+// an sql literal, e.g. 'O''Brien' for the string "O'Brien", NULL for nil or TRUE for
+// true. For mysql and mariadb the backslashes are escaped too, e.g. 'C:\\dir'. Times
+// are formatted as quoted timestamps with their time zone. It is only meant for
+// displaying sql commands, see Paginator.DebugSQL.
+func sqlLiteral(arg interface{}, dialect string) string {
+	if valuer, ok := arg.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return "NULL"
+		}
+		arg = v
+	}
+
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		if dialect == "mysql" || dialect == "mariadb" {
+			v = strings.ReplaceAll(v, `\`, `\\`)
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case []byte:
+		return sqlLiteral(string(v), dialect)
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999-07:00") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "NULL"
+		}
+		return sqlLiteral(rv.Elem().Interface(), dialect)
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(arg)
+	}
+	return sqlLiteral(fmt.Sprint(arg), dialect)
+}
+
+// escapeLikeValue escapes the wildcard characters of a LIKE pattern (% and _)
+// in the given value, so they are matched literally. The escape character
+// is the backslash, which is the default one in postgres and mysql.
+func escapeLikeValue(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return r.Replace(value)
+}
+
 func createPaginationClause(pageNumber int, pageSize int, c chan string) {
+	createOffsetPaginationClause(getOffset(pageNumber, pageSize), pageSize, c)
+}
+
+// createOffsetPaginationClause is like createPaginationClause but
+// the number of records to skip is given directly with offset.
+func createOffsetPaginationClause(offset int, pageSize int, c chan string) {
 	var clause string
-	var offset int
 
 	clause += fmt.Sprintf(" LIMIT %v ", pageSize)
+	clause += fmt.Sprintf("OFFSET %v", offset)
+
+	c <- clause
+}
 
+// createParameterizedPaginationClause creates a pagination clause whose LIMIT
+// and OFFSET values are placeholders. See PreparedPaginator.
+func createParameterizedPaginationClause(dialect string, c chan string) {
+	placeholder := _placeholder
+	c <- fmt.Sprintf(" LIMIT %s OFFSET %s", placeholder, placeholder)
+}
+
+// getOffset returns the number of records to skip to get the given page.
+func getOffset(pageNumber int, pageSize int) int {
 	if pageNumber < 0 || pageNumber == 0 || pageNumber == 1 {
-		offset = 0
-	} else {
-		offset = pageSize * (pageNumber - 1)
+		return 0
+	}
+	return pageSize * (pageNumber - 1)
+}
+
+// getTotalPages returns the number of pages of the given size needed to show the
+// given total number of records. It returns zero when the page size is zero.
+func getTotalPages(totalSize int, pageSize int) int {
+	if pageSize <= 0 || totalSize <= 0 {
+		return 0
 	}
+	return (totalSize + pageSize - 1) / pageSize
+}
 
-	clause += fmt.Sprintf("OFFSET %v", offset)
+// createOrderByClause creates the sql "ORDER BY" clause with the sort directives given
+// in the request parameter sortParam and the given customOrderByClauses. The records will
+// always be sorted by the given id at the end to make the sorting deterministic.
+//
+// The custom "ORDER BY" clauses come first, followed by the sort directives of the
+// request, so given the option OrderByDesc("salary") and the request ``sort=+name``:
+//
+//	ORDER BY salary DESC,name ASC,id
+//
+// Sort directives for the columns of the custom "ORDER BY" clauses will be ignored.
+//
+// The given nulls map holds the placement of the NULL values ("FIRST" or "LAST") for
+// the columns of the table. Custom "ORDER BY" clauses with an explicit placement of
+// NULL values will not be affected by nulls.
+func createOrderByClause(dialect string, params parameters, sortParam string, colNames []string, customOrderByClauses customOrderByClauses, ids []string, nulls map[string]string, quote bool, c chan string) {
+	clauses := mergeOrderByClauses(customOrderByClauses, getSortClauses(params, sortParam, colNames))
 
-	c <- clause
+	rendered := make([]string, 0, len(clauses)+1)
+	for _, clause := range clauses {
+		if clause.nulls == "" {
+			clause.nulls = nulls[clause.column]
+		}
+		if quote && !clause.expression {
+			clause.column = quoteIdentifier(dialect, clause.column)
+		}
+		rendered = append(rendered, clause.render(dialect))
+	}
+
+	// We will always order the records by ID, in order to keep the order between
+	// pages or results deterministic, unless the records are already sorted by ID,
+	// e.g. with ``sort=-id``, in which case the requested direction is respected.
+	// For composite ids the records are ordered by all the id columns.
+	// See: https://use-the-index-luke.com/sql/partial-results/fetch-next-page
+	for _, id := range ids {
+		if hasOrderByColumn(clauses, id) {
+			continue
+		}
+		if quote {
+			id = quoteIdentifier(dialect, id)
+		}
+		rendered = append(rendered, id)
+	}
+	clauseSTR := strings.Join(rendered, ",")
+	c <- " ORDER BY " + clauseSTR
 }
 
-func createOrderByClause(params parameters, colNames []string, customOrderByClauses customOrderByClauses, id string, c chan string) {
+// getSortClauses returns the "ORDER BY" clauses of the sort directives given in the
+// request parameter sortParam. Directives for unknown columns will be ignored.
+func getSortClauses(params parameters, sortParam string, colNames []string) []orderByClause {
 	var ASC = "ASC"
 	var DESC = "DESC"
 
-	clauses := make([]string, 0)
+	clauses := make([]orderByClause, 0)
 
-	sort, sortParamExists := params.getParameter("sort")
+	sort, sortParamExists := params.getParameter(sortParam)
 
 	if sortParamExists {
 		fields := strings.Split(sort.value, ",")
 		for _, v := range fields {
+			if len(v) < 2 {
+				continue
+			}
 			AscOrDesc := string(v[0])
 			field := v[1:]
 			for _, f := range colNames {
-				if f == id {
-					// we will always order the records by ID (see below). In order
-					// to keep the same order between pages or results deterministic.
-					// See: https://use-the-index-luke.com/sql/partial-results/fetch-next-page
-					continue
-				}
 				if field == f {
 					if AscOrDesc == "+" {
-						clauses = append(clauses, field+" "+ASC)
+						clauses = append(clauses, orderByClause{column: field, sorting: ASC})
 					}
 					if AscOrDesc == "-" {
-						clauses = append(clauses, field+" "+DESC)
+						clauses = append(clauses, orderByClause{column: field, sorting: DESC})
 					}
 				}
 			}
 		}
 	}
 
-	// As an special case if there are custom "ORDER BY" clauses
-	// we will add them to make the sorting correctly.
-	for _, customOrderBy := range customOrderByClauses {
-		clauses = append(clauses, customOrderBy.String())
+	return clauses
+}
+
+// hasOrderByColumn checks whether any of the given "ORDER BY" clauses,
+// other than expressions, sorts the records by the given column.
+func hasOrderByColumn(clauses []orderByClause, column string) bool {
+	for _, clause := range clauses {
+		if clause.column == column && !clause.expression {
+			return true
+		}
 	}
+	return false
+}
 
-	clauses = append(clauses, id)
-	clauseSTR := strings.Join(clauses, ",")
-	c <- " ORDER BY " + clauseSTR
+// mergeOrderByClauses returns the given custom "ORDER BY" clauses followed by the
+// given sort clauses. Only the first clause of every column will be kept, so the
+// sort clauses of columns that are already sorted by the custom ones are skipped.
+// Expressions are always kept since their arguments are bound anyway.
+func mergeOrderByClauses(customs customOrderByClauses, sorts []orderByClause) []orderByClause {
+	clauses := make([]orderByClause, 0, len(customs)+len(sorts))
+	seen := make(map[string]bool)
+	for _, clause := range append(append([]orderByClause{}, customs...), sorts...) {
+		if seen[clause.column] && !clause.expression {
+			continue
+		}
+		seen[clause.column] = true
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+// createCaseExpression creates the CASE expression of OrderByCase that sorts the
+// records by the position of the value of the given column among n values, e.g.:
+//
+//	CASE WHEN department = ? THEN 0 WHEN department = ? THEN 1 ELSE 2 END
+func createCaseExpression(column string, n int) string {
+	expr := "CASE"
+	for i := 0; i < n; i++ {
+		expr += fmt.Sprintf(" WHEN %s = %s THEN %d", column, _placeholder, i)
+	}
+	return expr + fmt.Sprintf(" ELSE %d END", n)
+}
+
+// createKeysetClause creates a RawWhereClause that matches the records that come
+// after the given values of the given "ORDER BY" clauses, taking into account the
+// sorting direction of every column, e.g. for "ORDER BY a ASC,b DESC,id":
+//
+//	(a > ? OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?))
+//
+// The NULL values of the given nullable columns are matched taking into account
+// where they are sorted, see nullsPlacement. So if "a" is nullable and its NULL
+// values are sorted last, a non-NULL value of "a" will give (a > ? OR a IS NULL),
+// while a NULL value will give a IS NULL instead of a = ? for the next columns.
+//
+// The given clauses and values should have the same length. If quote is true the
+// columns will be quoted, see QuoteIdentifiers.
+func createKeysetClause(dialect string, clauses []orderByClause, values []interface{}, nullable []string, quote bool) RawWhereClause {
+	raw := RawWhereClause{dialect: dialect}
+	predicates := make([]string, 0, len(clauses))
+
+	columns := make([]string, len(clauses))
+	for i, clause := range clauses {
+		columns[i] = clause.column
+		if quote {
+			columns[i] = quoteIdentifier(dialect, clause.column)
+		}
+	}
+
+	for i, clause := range clauses {
+		nulls := nullsPlacement(dialect, clause)
+
+		// No value comes after a NULL value sorted last.
+		if values[i] == nil && nulls == "LAST" {
+			continue
+		}
+
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				conditions = append(conditions, columns[j]+" IS NULL")
+				continue
+			}
+			conditions = append(conditions, columns[j]+" = ?")
+			raw.AddArg(values[j])
+		}
+		operator := ">"
+		if clause.sorting == "DESC" {
+			operator = "<"
+		}
+		switch {
+		case values[i] == nil:
+			// Only the non-NULL values come after a NULL value sorted first.
+			conditions = append(conditions, columns[i]+" IS NOT NULL")
+		case nulls == "LAST" && isStringIn(clause.column, nullable):
+			conditions = append(conditions, "("+columns[i]+" "+operator+" ? OR "+columns[i]+" IS NULL)")
+			raw.AddArg(values[i])
+		default:
+			conditions = append(conditions, columns[i]+" "+operator+" ?")
+			raw.AddArg(values[i])
+		}
+
+		if len(conditions) == 1 {
+			predicates = append(predicates, conditions[0])
+		} else {
+			predicates = append(predicates, "("+strings.Join(conditions, " AND ")+")")
+		}
+	}
+
+	raw.AddPredicate("(" + strings.Join(predicates, " OR ") + ")")
+	return raw
+}
+
+// nullsPlacement returns where the NULL values of the column of the given "ORDER BY"
+// clause are sorted, "FIRST" or "LAST". Unless the clause has an explicit placement,
+// postgres sorts the NULL values as if they were larger than any other value, while
+// mysql and mariadb sort them as if they were smaller.
+func nullsPlacement(dialect string, clause orderByClause) string {
+	if clause.nulls != "" {
+		return clause.nulls
+	}
+	larger := dialect == "postgres"
+	if (clause.sorting == "DESC") == larger {
+		return "FIRST"
+	}
+	return "LAST"
 }
 
 // parseCamelCaseToSnakeLowerCase parses a camelcase string to a snake case
 // lower cased. So for example, if we use as input for this function the following
 // string "myCamelCaseVar" the output would be "my_camel_case_var".
+//
+// Consecutive upper case letters are taken as acronyms, so "UserID" will be
+// "user_id" and "HTTPStatus" will be "http_status". Digits are kept with the
+// previous word, e.g. "OAuth2Token" will be "o_auth2_token".
 func parseCamelCaseToSnakeLowerCase(camelCase string) string {
-	var s []string
-	var lastIndexChecked int
-	var isSneakCase = true
-	var allAreUpperCase = true
+	runes := []rune(camelCase)
+	var b strings.Builder
 
-	for j := 0; j < len(camelCase); j++ {
-		if !unicode.IsUpper(rune(camelCase[j])) {
-			allAreUpperCase = false
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// A new word starts after a lower case letter or a digit, or at the
+			// last upper case letter of an acronym followed by a lower case one.
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
 		}
+		b.WriteRune(unicode.ToLower(r))
 	}
 
-	if allAreUpperCase {
-		return strings.ToLower(camelCase)
+	return b.String()
+}
+
+// qualifyColumn qualifies the given column with the given table name, e.g.
+// "employees.name". Columns that are already qualified with a table name
+// (e.g. "developer.programming_language") are returned as they are.
+func qualifyColumn(table, column string) string {
+	if strings.Contains(column, ".") {
+		return column
 	}
+	return table + "." + column
+}
 
-	for i := len(camelCase) - 1; i >= 0; i-- {
-		if unicode.IsUpper(rune(camelCase[i])) {
-			s = append(s, camelCase[i:])
-			camelCase = camelCase[:i]
-			lastIndexChecked = i
-			isSneakCase = false
-		}
+// filterValues returns the values of the given filter parameter that will be
+// given as arguments of the sql command, e.g. the bounds of a BETWEEN clause,
+// or none for the IS NULL and LIKE clauses, whose values are not converted.
+func filterValues(param parameter) []string {
+	switch param.sign {
+	case _isnull, _isnotnull, _like, _notlike:
+		return nil
+	case _in, _notin:
+		return strings.Split(param.value, ",")
+	case _between:
+		return strings.Split(param.value, rangesep)
+	default:
+		return []string{param.value}
+	}
+}
 
-		if i == 0 && lastIndexChecked > 0 {
-			s = append(s, camelCase[:lastIndexChecked])
-		}
+// parseBool parses the given value of a boolean column given in the request,
+// which should be true, false, 1 or 0. The words are case insensitive.
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "1":
+		return true, true
+	case "false", "0":
+		return false, true
 	}
+	return false, false
+}
 
-	if isSneakCase {
-		return strings.ToLower(camelCase)
+// quoteIdentifier quotes the given identifier for the given dialect, i.e. with
+// backticks for mysql and mariadb and with double quotes for postgres, so
+// identifiers that are reserved words can be used, e.g. "order". Each part of
+// a qualified identifier is quoted separately, e.g. "employees"."order".
+func quoteIdentifier(dialect, identifier string) string {
+	quote := `"`
+	if dialect == "mysql" || dialect == "mariadb" {
+		quote = "`"
+	}
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
 	}
+	return strings.Join(parts, ".")
+}
+
+// isNullableType checks whether the given type is one of the
+// nullable types supported by this package.
+func isNullableType(t reflect.Type) bool {
+	switch reflect.Zero(t).Interface().(type) {
+	case NullInt, NullInt64, NullInt32, NullBool, NullString, NullTime, NullFloat64, NullFloat32, NullDecimal:
+		return true
+	default:
+		return false
+	}
+}
+
+// isNullableField checks whether a struct field of the given type can hold
+// NULL values, i.e. whether it is one of the nullable types supported by this
+// package or a pointer.
+func isNullableField(t reflect.Type) bool {
+	return isNullableType(t) || t.Kind() == reflect.Ptr
+}
+
+// isInvertedRange checks whether the given lower bound of a range is greater than
+// the given upper bound, comparing them as times or as numbers. Bounds that are
+// not comparable are not inverted.
+func isInvertedRange(lower, upper interface{}) bool {
+	if l, ok := lower.(time.Time); ok {
+		u, ok := upper.(time.Time)
+		return ok && u.Before(l)
+	}
+	l, lok := lower.(string)
+	u, uok := upper.(string)
+	if !lok || !uok {
+		return false
+	}
+	lf, err := strconv.ParseFloat(l, 64)
+	if err != nil {
+		return false
+	}
+	uf, err := strconv.ParseFloat(u, 64)
+	return err == nil && uf < lf
+}
 
-	var orderedSlice []string
-	for i := len(s) - 1; i >= 0; i-- {
-		orderedSlice = append(orderedSlice, s[i])
+// isRangeType checks whether the columns of the given type can be filtered with
+// ranges like ``4000..8000``, i.e. the numbers and the times, including their
+// nullable types and pointers.
+func isRangeType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
 	}
+	switch reflect.Zero(t).Interface().(type) {
+	case time.Time, NullInt, NullInt64, NullInt32, NullTime, NullFloat64, NullFloat32, NullDecimal:
+		return true
+	default:
+		return false
+	}
+}
 
-	return strings.ToLower(strings.Join(orderedSlice, "_"))
+// sqlIdentifierRegexp matches the bare or qualified identifiers of a sql predicate,
+// e.g. "name" or "employee.name". See validateRawIdentifiers.
+var sqlIdentifierRegexp = regexp.MustCompile(`\b[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?`)
+
+// sqlStringLiteralRegexp matches the string literals of a sql predicate, e.g. 'ringo'.
+var sqlStringLiteralRegexp = regexp.MustCompile(`'([^']|'')*'`)
+
+// sqlKeywords holds the sql keywords that can be used in the predicates of the
+// raw where clauses checked by validateRawIdentifiers.
+var sqlKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "NULL": true,
+	"LIKE": true, "ILIKE": true, "BETWEEN": true, "TRUE": true, "FALSE": true,
+	"CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
+	"ANY": true, "ALL": true, "SOME": true, "ESCAPE": true, "DISTINCT": true,
+	"FROM": true, "INTERVAL": true, "SIMILAR": true, "TO": true, "AS": true,
+}
+
+// validateRawIdentifiers checks that the identifiers referenced by the given
+// predicate of a raw where clause are columns of the given table or columns
+// qualified by one of the given allowed tables. The sql keywords, the names of
+// the sql functions, i.e. identifiers followed by "(", the types of the sql casts,
+// i.e. identifiers preceded by "::" or "AS", and the string literals are skipped.
+// Predicates with sql comments or backslashes are rejected.
+func validateRawIdentifiers(predicate, table string, colNames, allowedTables []string) error {
+	// Comments and backslash escapes could hide identifiers from the tokenization,
+	// e.g. an apostrophe inside a comment would be taken as a string literal.
+	for _, token := range []string{"/*", "*/", "--", "#", "\\"} {
+		if strings.Contains(predicate, token) {
+			return fmt.Errorf("paginate: the raw where clause cannot contain %q", token)
+		}
+	}
+	predicate = sqlStringLiteralRegexp.ReplaceAllString(predicate, "''")
+	for _, loc := range sqlIdentifierRegexp.FindAllStringIndex(predicate, -1) {
+		ident := predicate[loc[0]:loc[1]]
+		if sqlKeywords[strings.ToUpper(ident)] {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimLeft(predicate[loc[1]:], " "), "(") {
+			continue
+		}
+		if before := strings.TrimRight(predicate[:loc[0]], " "); strings.HasSuffix(before, "::") || strings.HasSuffix(strings.ToUpper(before), " AS") {
+			continue
+		}
+		if i := strings.Index(ident, "."); i >= 0 {
+			qualifier, column := ident[:i], ident[i+1:]
+			if isStringIn(qualifier, allowedTables) || (qualifier == table && isStringIn(column, colNames)) {
+				continue
+			}
+		} else if isStringIn(ident, colNames) {
+			continue
+		}
+		return fmt.Errorf("paginate: the raw where clause references %q which is not a column of the table", ident)
+	}
+	return nil
+}
+
+// indexOf returns the index of the given string ``s`` in the given
+// slice ``in``, or -1 if ``s`` is not in ``in``.
+func indexOf(s string, in []string) int {
+	for i, elem := range in {
+		if s == elem {
+			return i
+		}
+	}
+	return -1
 }
 
 // isStringIn checks whether the given string ``s`` is in the given slice ``in``.
@@ -409,3 +1325,57 @@ func isStringIn(s string, in []string) bool {
 	}
 	return false
 }
+
+// isParameterIn checks whether the given parameter ``p`` is in the given slice ``in``.
+func isParameterIn(p parameter, in parameters) bool {
+	for _, elem := range in {
+		if p == elem {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCursor returns a continuation token with the given values encoded as
+// a base64 JSON array. Values implementing driver.Valuer are encoded with the
+// value they give to the database.
+func encodeCursor(values []interface{}) string {
+	encoded := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err == nil {
+				value = v
+			}
+		}
+		encoded = append(encoded, value)
+	}
+	b, _ := json.Marshal(encoded)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor returns the values encoded in the given continuation token.
+// Numbers are returned as strings to not lose precision. Only scalar values,
+// i.e. strings, numbers, booleans and nulls, are accepted, since the values
+// are given to the driver as arguments of the keyset clause.
+func decodeCursor(token string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	values := make([]interface{}, 0)
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		switch v := value.(type) {
+		case json.Number:
+			values[i] = v.String()
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("paginate: continuation token value %d is not a scalar", i)
+		}
+	}
+	return values, nil
+}
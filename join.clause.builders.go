@@ -1,29 +1,256 @@
 package paginate
 
-import "strings"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// JoinClause is implemented by the join clause builders of this package,
+// i.e. InnerJoin, LeftJoin, RightJoin and RawJoinClause. Use Paginator.AddJoinClause
+// to add a JoinClause to a Paginator.
+type JoinClause interface {
+	joinClause() joinClause
+}
 
 func NewInnerJoinClause(dialect string) (InnerJoin, error) {
 	if err := dialectPlaceholder.CheckIfDialectIsSupported(dialect); err != nil {
 		return InnerJoin{}, err
 	}
-	return InnerJoin{dialect: dialect}, nil
+	return InnerJoin{joinBuilder{kind: "JOIN", dialect: dialect}}, nil
 }
 
 type InnerJoin struct {
-	column       string
-	targetTable  string
-	targetColumn string
-	dialect      string
+	joinBuilder
+}
+
+// NewLeftJoinClause will give you a validated instance of a LeftJoin object.
+//
+// Use a LeftJoin whenever the rows of the paginated table should be kept
+// even if they do not have a matching row in the joined table. In that case
+// the columns of the joined table will be scanned as their zero values.
+func NewLeftJoinClause(dialect string) (LeftJoin, error) {
+	if err := dialectPlaceholder.CheckIfDialectIsSupported(dialect); err != nil {
+		return LeftJoin{}, err
+	}
+	return LeftJoin{joinBuilder{kind: "LEFT JOIN", dialect: dialect}}, nil
+}
+
+type LeftJoin struct {
+	joinBuilder
+}
+
+// rightJoinDialects holds the dialects that support RIGHT JOIN.
+var rightJoinDialects = map[string]bool{
+	"mysql":    true,
+	"mariadb":  true,
+	"postgres": true,
+}
+
+// NewRightJoinClause will give you a validated instance of a RightJoin object.
+//
+// Use a RightJoin whenever the rows of the joined table should be kept even if
+// they do not have a matching row in the paginated table. In that case the
+// columns of the paginated table will be scanned as their zero values.
+func NewRightJoinClause(dialect string) (RightJoin, error) {
+	if err := dialectPlaceholder.CheckIfDialectIsSupported(dialect); err != nil {
+		return RightJoin{}, err
+	}
+	if !rightJoinDialects[dialect] {
+		return RightJoin{}, fmt.Errorf("paginate: dialect %q does not support RIGHT JOIN", dialect)
+	}
+	return RightJoin{joinBuilder{kind: "RIGHT JOIN", dialect: dialect}}, nil
 }
 
-func (clause *InnerJoin) On(column, targetTable, targetColumn string) {
-	clause.column = column
-	clause.targetTable = targetTable
-	clause.targetColumn = targetColumn
+type RightJoin struct {
+	joinBuilder
 }
 
-func (clause *InnerJoin) clean() {
-	clause.column = strings.TrimSpace(clause.column)
-	clause.targetTable = strings.TrimSpace(clause.targetTable)
-	clause.targetColumn = strings.TrimSpace(clause.targetColumn)
+// joinBuilder holds the state shared by the InnerJoin, LeftJoin and RightJoin
+// builders, which only differ in their kind of join.
+type joinBuilder struct {
+	// kind is the sql join type, e.g. "JOIN", "LEFT JOIN" or "RIGHT JOIN".
+	kind string
+
+	targetTable string
+	conditions  joinConditions
+	selections  map[string]string
+	dialect     string
+}
+
+// On sets the first condition of the join clause. The given column of the
+// paginated table will be matched with the targetColumn of the targetTable.
+// Use AndOn to add more conditions to the join clause.
+func (b *joinBuilder) On(column, targetTable, targetColumn string) *joinBuilder {
+	b.targetTable = targetTable
+	b.conditions = joinConditions{{column: column, targetColumn: targetColumn}}
+	return b
+}
+
+// AndOn adds an extra condition to the join clause which will be joined
+// with the previous conditions with AND.
+func (b *joinBuilder) AndOn(column, targetColumn string) *joinBuilder {
+	b.conditions = append(b.conditions, joinCondition{column: column, targetColumn: targetColumn})
+	return b
+}
+
+// Select maps the given struct fields of the paginated table with columns of the
+// targetTable, e.g. {"Language": "programming_language"}, so the fields will be
+// selected, filtered and sorted as the qualified joined columns, e.g. with
+// developer.programming_language. The fields are still filtered and sorted with
+// the request parameters of their original columns.
+func (b *joinBuilder) Select(columns map[string]string) *joinBuilder {
+	b.selections = columns
+	return b
+}
+
+func (b joinBuilder) joinClause() joinClause {
+	return joinClause{
+		kind:        b.kind,
+		targetTable: strings.TrimSpace(b.targetTable),
+		conditions:  b.conditions.clean(),
+		selections:  b.selections,
+		dialect:     b.dialect,
+	}
+}
+
+// NewRawJoinClause will give you a validated instance of a RawJoinClause object.
+//
+// Use a RawJoinClause whenever you need a join that the other join clause builders
+// cannot express, e.g. a lateral join in postgres or a join with a subquery.
+//
+// Example of how to use the returned RawJoinClause object:
+//
+//  ...
+//  paginator, _ := NewPaginator(MyTable{}, "postgres", *url)
+//  rawJoinSql, err := NewRawJoinClause("postgres")
+//  if err != nil {
+//     // Handle error gracefully.
+//  }
+//
+//  rawJoinSql.AddRaw("JOIN LATERAL (SELECT count(*) AS n FROM task WHERE task.employee_id = employees.id AND task.done = ?) AS tasks ON true")
+//  rawJoinSql.AddArg(false)
+//
+//  err = paginator.AddJoinClause(rawJoinSql)
+//  if err != nil {
+//     // Handle error gracefully.
+//  }
+//
+func NewRawJoinClause(dialect string) (RawJoinClause, error) {
+	if err := dialectPlaceholder.CheckIfDialectIsSupported(dialect); err != nil {
+		return RawJoinClause{}, err
+	}
+	return RawJoinClause{dialect: dialect}, nil
+}
+
+type RawJoinClause struct {
+	sql     string
+	args    []interface{}
+	dialect string
+}
+
+// AddRaw sets the given sql join fragment of the RawJoinClause instance, which
+// should start with a join keyword, e.g. "JOIN", "LEFT JOIN" or "CROSS JOIN".
+// If the fragment requires arguments use the question mark symbol "?" as
+// placeholders, later use AddArg to add the arguments you need.
+func (raw *RawJoinClause) AddRaw(sql string) {
+	raw.sql = sql
+}
+
+// AddArg adds the given argument to the RawJoinClause instance.
+// If the join fragment requires more than one argument you
+// should call AddArg multiple times.
+func (raw *RawJoinClause) AddArg(v interface{}) {
+	raw.args = append(raw.args, v)
+}
+
+func (raw RawJoinClause) joinClause() joinClause {
+	return joinClause{
+		raw:     strings.TrimSpace(raw.sql),
+		args:    raw.args,
+		dialect: raw.dialect,
+	}
+}
+
+// rawJoinRegexp matches the sql join fragments that can be given to a
+// RawJoinClause, i.e. the fragments starting with a join keyword.
+var rawJoinRegexp = regexp.MustCompile(`(?i)^(NATURAL\s+)?((INNER|CROSS|LEFT|RIGHT|FULL)(\s+OUTER)?\s+)?JOIN\s`)
+
+// joinClause holds the information of a join clause given by any of the
+// join clause builders. See Paginator.AddJoinClause.
+type joinClause struct {
+	// kind is the sql join type, e.g. "JOIN", "LEFT JOIN" or "RIGHT JOIN".
+	kind string
+
+	// table is the name of the paginated table.
+	table string
+
+	targetTable string
+	conditions  joinConditions
+	dialect     string
+
+	// selections maps struct fields of the paginated table with columns
+	// of the targetTable. See InnerJoin.Select.
+	selections map[string]string
+
+	// raw is the sql join fragment given by a RawJoinClause, and args are
+	// its arguments. The other fields, except dialect, are empty in that case.
+	raw  string
+	args []interface{}
+
+	// conditional indicates whether the join clause should only be added to
+	// the sql command when the joined table is referenced. See
+	// Paginator.AddConditionalJoinClause.
+	conditional bool
+}
+
+// String returns the join clause as an sql string, e.g.:
+//
+//	JOIN manager ON employees.id = manager.employee_id
+func (j joinClause) String() string {
+	return j.render(false)
+}
+
+// render returns the join clause as an sql string like String, but with the
+// tables and columns quoted if quote is true, e.g.:
+//
+//	JOIN "manager" ON "employees"."id" = "manager"."employee_id"
+//
+// The sql fragments of a RawJoinClause are never quoted.
+func (j joinClause) render(quote bool) string {
+	if j.raw != "" {
+		return j.raw
+	}
+	identifier := func(s string) string {
+		if quote {
+			return quoteIdentifier(j.dialect, s)
+		}
+		return s
+	}
+	conditions := make([]string, 0, len(j.conditions))
+	for _, c := range j.conditions {
+		conditions = append(conditions, fmt.Sprintf("%s = %s",
+			identifier(j.table+"."+c.column), identifier(j.targetTable+"."+c.targetColumn)))
+	}
+	return fmt.Sprintf("%s %s ON %s", j.kind, identifier(j.targetTable), strings.Join(conditions, " AND "))
+}
+
+// joinConditions holds the conditions of a join clause.
+type joinConditions []joinCondition
+
+// joinCondition matches a column of the paginated table with a
+// column of the joined table.
+type joinCondition struct {
+	column, targetColumn string
+}
+
+func (conditions joinConditions) clean() joinConditions {
+	cleaned := make(joinConditions, 0, len(conditions))
+	for _, c := range conditions {
+		cleaned = append(cleaned, joinCondition{
+			column:       strings.TrimSpace(c.column),
+			targetColumn: strings.TrimSpace(c.targetColumn),
+		})
+	}
+	return cleaned
 }
@@ -1,6 +1,7 @@
 package paginate
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
@@ -85,6 +86,92 @@ func (ni *NullInt) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// NullInt64 is like NullInt but always backed by an int64, regardless of
+// the platform, e.g. for BIGINT columns.
+type NullInt64 struct {
+	Int64 int64
+	Valid bool // Valid is true if Int64 is not NULL
+}
+
+func (n *NullInt64) Scan(value interface{}) error {
+	var ni64 sql.NullInt64
+	if err := ni64.Scan(value); err != nil {
+		return err
+	}
+	n.Int64, n.Valid = ni64.Int64, ni64.Valid
+	return nil
+}
+
+func (n NullInt64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Int64, nil
+}
+
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Int64)
+}
+
+func (n *NullInt64) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.Int64, n.Valid = 0, false
+		return nil
+	}
+	int64Val, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	n.Int64, n.Valid = int64Val, true
+	return nil
+}
+
+// NullInt32 is like NullInt but always backed by an int32, e.g. for INTEGER
+// columns. Scanning a value that does not fit in an int32 will fail.
+type NullInt32 struct {
+	Int32 int32
+	Valid bool // Valid is true if Int32 is not NULL
+}
+
+func (n *NullInt32) Scan(value interface{}) error {
+	var ni32 sql.NullInt32
+	if err := ni32.Scan(value); err != nil {
+		return err
+	}
+	n.Int32, n.Valid = ni32.Int32, ni32.Valid
+	return nil
+}
+
+func (n NullInt32) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Int32), nil
+}
+
+func (n NullInt32) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Int32)
+}
+
+func (n *NullInt32) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.Int32, n.Valid = 0, false
+		return nil
+	}
+	int32Val, err := strconv.ParseInt(string(data), 10, 32)
+	if err != nil {
+		return err
+	}
+	n.Int32, n.Valid = int32(int32Val), true
+	return nil
+}
+
 type NullBool struct {
 	Bool  bool
 	Valid bool // Valid is true if Bool is not NULL
@@ -203,8 +290,22 @@ func (nt *NullTime) Scan(value interface{}) error {
 		nt.Time, nt.Valid = time.Time{}, false
 		return nil
 	}
-	timeVal, ok := value.(time.Time)
-	if !ok {
+	var timeVal time.Time
+	switch t := value.(type) {
+	case time.Time:
+		timeVal = t
+	// As an special case when passing custom types that implement the Scanner interface to the
+	// driver "go-sql-driver/mysql", the driver will return []uint8 unless the parseTime parameter
+	// is set. Therefore, we need to try to parse that returned value to string and finally to time
+	// with the common layouts of the timestamps, which the driver gives in UTC by default.
+	// Check issue: https://github.com/go-sql-driver/mysql/issues/441
+	case []uint8:
+		_time, ok := parseTimeInLocation(string(t), time.UTC)
+		if !ok {
+			return fmt.Errorf("column is not timestamp")
+		}
+		timeVal = _time
+	default:
 		return fmt.Errorf("column is not timestamp")
 	}
 	nt.Valid = true
@@ -259,6 +360,16 @@ func (n *NullFloat64) Scan(value interface{}) error {
 		float64Val = float64(t)
 	case float64:
 		float64Val = t
+	// As an special case when passing custom types that implement the Scanner interface to the
+	// driver "go-sql-driver/mysql", the driver will return []uint8. Therefore, we need to try to parse that returned
+	// value to string and finally to float64 in this case if possible.
+	// Check issue: https://github.com/go-sql-driver/mysql/issues/441
+	case []uint8:
+		_float64, err := strconv.ParseFloat(string(t), 64)
+		if err != nil {
+			return err
+		}
+		float64Val = _float64
 	default:
 		return fmt.Errorf("column is not float64")
 	}
@@ -295,3 +406,168 @@ func (n *NullFloat64) UnmarshalJSON(data []byte) error {
 	n.Float64, n.Valid = float64Val, true
 	return nil
 }
+
+// NullFloat32 is like NullFloat64 but backed by a float32, e.g. for REAL
+// columns.
+type NullFloat32 struct {
+	Float32 float32
+	Valid   bool // Valid is true if Float32 is not NULL
+}
+
+func (n *NullFloat32) Scan(value interface{}) error {
+	var nf64 sql.NullFloat64
+	if err := nf64.Scan(value); err != nil {
+		return err
+	}
+	n.Float32, n.Valid = float32(nf64.Float64), nf64.Valid
+	return nil
+}
+
+func (n NullFloat32) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return float64(n.Float32), nil
+}
+
+func (n NullFloat32) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float32)
+}
+
+func (n *NullFloat32) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.Float32, n.Valid = 0, false
+		return nil
+	}
+
+	float32Val, err := strconv.ParseFloat(string(data), 32)
+	if err != nil {
+		return err
+	}
+
+	n.Float32, n.Valid = float32(float32Val), true
+	return nil
+}
+
+// NullDecimal holds the value of a NUMERIC or DECIMAL column as the string
+// given by the database, e.g. "1234.50", so no precision is lost as it would
+// be with a float64. It is marshaled to a JSON number.
+type NullDecimal struct {
+	Decimal string
+	Valid   bool // Valid is true if Decimal is not NULL
+}
+
+func (n *NullDecimal) Scan(value interface{}) error {
+	if value == nil {
+		n.Decimal, n.Valid = "", false
+		return nil
+	}
+	var decimalVal string
+	switch t := value.(type) {
+	case string:
+		decimalVal = t
+	// The drivers will usually return the numeric columns as []uint8.
+	case []uint8:
+		decimalVal = string(t)
+	case int64:
+		decimalVal = strconv.FormatInt(t, 10)
+	case float64:
+		decimalVal = strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Errorf("column is not decimal")
+	}
+	n.Valid = true
+	n.Decimal = decimalVal
+	return nil
+}
+
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Decimal, nil
+}
+
+func (n NullDecimal) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(json.Number(n.Decimal))
+}
+
+func (n *NullDecimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.Decimal, n.Valid = "", false
+		return nil
+	}
+
+	// Decimals given as json strings, e.g. "1234.50", are accepted too.
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+
+	n.Decimal, n.Valid = number.String(), true
+	return nil
+}
+
+// nullUint is the scan target used by Paginator for the fields of the
+// uint family (uint, uint8, uint16, uint32, uint64). It scans the value
+// through sql.NullInt64 and checks that it fits in an unsigned integer
+// of the given bit size before narrowing it, so a negative value or a
+// value that is too big for the field will fail the scan instead of
+// overflowing silently.
+type nullUint struct {
+	Uint    uint64
+	Valid   bool // Valid is true if Uint is not NULL
+	bitSize int
+}
+
+func (nu *nullUint) Scan(value interface{}) error {
+	var ni64 sql.NullInt64
+	if err := ni64.Scan(value); err != nil {
+		return err
+	}
+	if !ni64.Valid {
+		nu.Uint, nu.Valid = 0, false
+		return nil
+	}
+	if ni64.Int64 < 0 {
+		return fmt.Errorf("paginate: cannot scan negative value %d into an unsigned field", ni64.Int64)
+	}
+	if nu.bitSize < 64 && uint64(ni64.Int64) >= 1<<uint(nu.bitSize) {
+		return fmt.Errorf("paginate: value %d overflows an unsigned field of %d bits", ni64.Int64, nu.bitSize)
+	}
+	nu.Uint, nu.Valid = uint64(ni64.Int64), true
+	return nil
+}
+
+// nullInt is the scan target used by Paginator for the int8 and int16
+// fields. Like nullUint, it scans the value through sql.NullInt64 and
+// checks that it fits in a signed integer of the given bit size before
+// narrowing it, so a value that is too big for the field will fail the
+// scan instead of overflowing silently.
+type nullInt struct {
+	Int     int64
+	Valid   bool // Valid is true if Int is not NULL
+	bitSize int
+}
+
+func (ni *nullInt) Scan(value interface{}) error {
+	var ni64 sql.NullInt64
+	if err := ni64.Scan(value); err != nil {
+		return err
+	}
+	if !ni64.Valid {
+		ni.Int, ni.Valid = 0, false
+		return nil
+	}
+	if limit := int64(1) << uint(ni.bitSize-1); ni64.Int64 < -limit || ni64.Int64 >= limit {
+		return fmt.Errorf("paginate: value %d overflows a signed field of %d bits", ni64.Int64, ni.bitSize)
+	}
+	ni.Int, ni.Valid = ni64.Int64, true
+	return nil
+}
@@ -1,11 +1,16 @@
 package paginate
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +21,37 @@ import (
 // already scanned.
 var ErrPaginatorIsClosed = errors.New("paginate: Paginator is closed")
 
+// ErrOffsetTooDeep is an error returned by NewPaginator and Paginator.SetPage
+// when the offset of the requested page exceeds the limit given with the
+// MaxOffset option and the StrictPaging option is used.
+var ErrOffsetTooDeep = errors.New("paginate: the offset of the page exceeds the max offset")
+
+// Querier is the interface that wraps the QueryContext method. It is
+// satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Preparer is the interface that wraps the PrepareContext method. It is
+// satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type Preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// observedQuerier is a Querier that calls the given hook with every sql command
+// it runs, its arguments and the time it took to run it. See the OnQuery option.
+type observedQuerier struct {
+	q    Querier
+	hook func(sql string, args []interface{}, elapsed time.Duration)
+}
+
+func (o observedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := o.q.QueryContext(ctx, query, args...)
+	o.hook(query, args, time.Since(start))
+	return rows, err
+}
+
 // Paginator wraps pagination behaviors.
 //
 // Paginator should be used following the next steps in the same order:
@@ -27,10 +63,17 @@ var ErrPaginatorIsClosed = errors.New("paginate: Paginator is closed")
 // 		5. Call Scan inside the NextData loop to copy the paginated data to the given destination.
 // 		6. Call Response to get useful information about the pagination operation.
 //
+// Steps 2 and 3 can be replaced with a single call to Execute. To fetch another
+// page with the same Paginator call Reset and SetPage and repeat the steps from 2.
+//
 // For more information, see the examples folder to check how to use Paginator.
 type Paginator interface {
 	// Paginate will return an sql command with the corresponding arguments,
 	// so it can be run against any sql driver.
+	//
+	// For the mariadb dialect the sql command will not use the count(*) over()
+	// window function to select the total number of records. Use Execute instead,
+	// so Paginator can get the total number of records with a separate query.
 	Paginate() (sql string, args []interface{}, err error)
 
 	// GetRowPtrArgs will prepare the next pointer arguments that can be scanned
@@ -51,9 +94,85 @@ type Paginator interface {
 	// internally in the Paginator object so you can read/scan them later.
 	GetRowPtrArgs() []interface{}
 
+	// Prepare prepares the sql command created by Paginate with placeholders for
+	// the LIMIT and OFFSET values, so the same prepared statement can be reused to
+	// fetch any page of the paginated data. See PreparedPaginator.
+	Prepare(ctx context.Context, db Preparer) (*PreparedPaginator, error)
+
+	// Execute is a convenience method that runs the sql command created by Paginate
+	// with the given Querier and scans all the returned rows with GetRowPtrArgs.
+	// The rows are closed before Execute returns. After calling Execute use NextData
+	// and Scan to read the paginated data, for example:
+	//
+	//	err := paginator.Execute(ctx, db)
+	//	if err != nil {
+	//		log.Fatal(err)
+	//	}
+	//	for paginator.NextData() {
+	//		...
+	//	}
+	//
+	// The given context can be used to cancel the query, e.g. on request timeouts.
+	// For the mariadb dialect Execute will run a separate count query first to get
+	// the total number of records.
+	Execute(ctx context.Context, q Querier) error
+
+	// ForEach is like Execute, but instead of keeping the rows of the page for
+	// NextData and Scan it calls fn with every record as soon as it is scanned,
+	// so large pages are not held in memory. The given record is a value of the
+	// type of the given ``table``, for example:
+	//
+	//	err := paginator.ForEach(ctx, db, func(row interface{}) error {
+	//		employee := row.(Employee)
+	//		...
+	//		return nil
+	//	})
+	//
+	// ForEach stops at the first error returned by fn and returns it. The rows are
+	// closed before ForEach returns. Response and LastModified can be used after
+	// ForEach like after Execute.
+	ForEach(ctx context.Context, q Querier, fn func(row interface{}) error) error
+
+	// IDs is a lightweight alternative to Execute that runs an sql command with the
+	// given Querier selecting only the ids of the records of the current page. The
+	// filters, sorting, and pagination of the records are the same as Paginate.
+	// The returned ids will have the type of the struct field tagged with "id".
+	// This is useful, for example, to fetch the ids first and then hydrate the
+	// records from a cache. IDs does not affect the state of the Paginator.
+	IDs(ctx context.Context, q Querier) ([]interface{}, error)
+
+	// Count runs an sql command with the given Querier that counts the total number
+	// of records matching the filters of the Paginator, without LIMIT and OFFSET.
+	// This is useful to get the total size of the pagination before fetching the
+	// records, e.g. to set a Content-Range header. Count does not affect the state
+	// of the Paginator.
+	Count(ctx context.Context, q Querier) (int, error)
+
+	// QueryShape returns the sql command created by Paginate with all its
+	// placeholders and literal values (e.g. the LIMIT and OFFSET values)
+	// normalized to "?". Commands that only differ in their argument values
+	// or in the requested page have the same shape, so the shape can be used,
+	// for example, as a cache key or as a metrics label. QueryShape returns
+	// an empty string if the sql command cannot be created.
+	QueryShape() string
+
+	// DebugSQL returns the sql command created by Paginate with its arguments
+	// interpolated into the placeholders as sql literals, e.g. strings will be
+	// quoted and nil values will be NULL, so it can be copied into an sql console
+	// for debugging or logged. DebugSQL returns an empty string if the sql command
+	// cannot be created. Never execute the sql command returned by DebugSQL, since
+	// the interpolation of the arguments is not safe against sql injection. Use
+	// Paginate instead.
+	DebugSQL() string
+
 	// NextData will loop over the saved values created by GetRowPtrArgs until
 	// all the paginated data has been scanned by Scan. Always use NextData
 	// followed by a call to Scan.
+	//
+	// NextData and Scan can be called from several goroutines sharing the same
+	// Paginator, every row will be scanned only once. In that case Scan might
+	// return ErrPaginatorIsClosed even if NextData returned true, when another
+	// goroutine scanned the last row in between.
 	NextData() bool
 
 	// Scan will copy the next paginated data in the given destination. The given destination
@@ -81,15 +200,80 @@ type Paginator interface {
 	// 	  - sql.NullBool
 	// 	  - sql.NullTime
 	//
+	// Nullable fields of the uint family (uint, uint8, uint16, uint32, uint64) and of
+	// type int8 or int16 will also be converted to zero. Scanning a negative value or a
+	// value too big for an unsigned field, or a value out of the range of an int8 or
+	// int16 field, will return an error.
+	//
 	// For other nullable fields that you might want Scan to handle, use
-	// the nullable types provided by this package.
+	// the nullable types provided by this package, e.g. NullInt64 or NullInt32 for
+	// integers whose width should not depend on the platform, or NullDecimal for
+	// NUMERIC columns that should not lose precision. Scan also handles nullable
+	// columns with the pointer types *string, *int, *int64, *float64, *bool,
+	// and *time.Time. The pointers will be nil when the values are NULL.
 	Scan(dest interface{}) error
 
+	// ScanMap is like Scan but returns the next paginated row as a map keyed by the
+	// column names of the table, i.e. the names given in the ``col`` tags or the
+	// snake case names of the struct fields. NULL values are mapped to nil and the
+	// nullable values to their underlying value, like in ScanRows. ScanMap is useful
+	// for schema-flexible consumers, e.g. exports. Always use NextData followed by a
+	// call to ScanMap.
+	ScanMap() (map[string]interface{}, error)
+
+	// ScanRows returns the paginated rows that have not been scanned yet as
+	// slices of values in the same order as the columns of the sql command.
+	// The nullable values are converted to nil when they are NULL or to their
+	// underlying value otherwise, e.g. a sql.NullString will be converted to a
+	// string. ScanRows is an alternative to NextData and Scan for generic tools
+	// that do not know the given table.
+	ScanRows() ([][]interface{}, error)
+
+	// ScanMaps is like ScanRows but returns every row as a map. The keys of the
+	// maps are the names given in the ``json`` tags of the fields of the table,
+	// so the maps can be marshalled with the same field names as the table, or
+	// the column names for the fields without a ``json`` tag. Fields with the
+	// tag ``json:"-"`` are left out of the maps.
+	ScanMaps() ([]map[string]interface{}, error)
+
+	// Remaining returns the number of paginated rows that have not been scanned
+	// yet by Scan, ScanRows or ScanMaps.
+	Remaining() int
+
+	// Consumed returns the number of paginated rows that have been scanned by
+	// Scan, ScanRows or ScanMaps, or given to the callback of ForEach. Together
+	// with Remaining it is useful, for example, to log how many rows were
+	// processed when a Scan error stopped the iteration early.
+	Consumed() int
+
 	// Response returns a PaginationResponse containing useful information about
 	// the pagination, so that clients can do proper and subsequent pagination
-	// operations.
+	// operations. Response can also be called when the query or the scanning of
+	// the records failed, e.g. for logging the requested page, in which case the
+	// PaginationResponse will be marked as partial.
 	Response() PaginationResponse
 
+	// Reset clears the paginated data and the state of the scanning, so the same
+	// Paginator can be used to query the table again, e.g. to fetch another page
+	// given with SetPage, without parsing the given table again. The filters,
+	// sorting and options of the Paginator are kept. Reset must be called before
+	// querying the table again, otherwise the new rows cannot be scanned.
+	Reset()
+
+	// SetPage sets the number of the page that the next sql command created by
+	// Paginate will fetch. Call Reset before fetching the new page. With the
+	// StrictPaging option SetPage returns an error when the given page is not
+	// greater than zero, otherwise the first page is used instead.
+	SetPage(n int) error
+
+	// LastModified returns the most recent timestamp of the last modified column
+	// among the rows scanned with GetRowPtrArgs. Use this, for example, to set the
+	// Last-Modified header of an http response. By default the last modified column
+	// is "updated_at", see the LastModifiedColumn option to use a different column.
+	// LastModified returns false when the table does not have the last modified
+	// column or when there are no scanned rows with a non-null value in that column.
+	LastModified() (time.Time, bool)
+
 	// AddWhereClause adds a custom raw where clause that paginator can use to
 	// filter out the rows of the target table in the database. Usually, you will
 	// use this when the backend needs to filter the records based on internal logic,
@@ -97,9 +281,47 @@ type Paginator interface {
 	// calling Paginator.Paginate.
 	AddWhereClause(clause RawWhereClause) error
 
+	// AddHavingClause adds a custom raw having clause that paginator will use to
+	// filter out the groups created with the GroupBy option, e.g. "count(*) > ?".
+	// The having clauses will be wrapped in parentheses and combined with AND. Add
+	// having clauses before calling Paginator.Paginate.
+	AddHavingClause(clause RawHavingClause) error
+
 	// AddJoinClause adds a custom join clause that paginator can use to join
-	// multiple tables and columns for pagination.
-	AddJoinClause(clause interface{}) error
+	// multiple tables and columns for pagination. Use the join clause builders
+	// NewInnerJoinClause, NewLeftJoinClause and NewRightJoinClause to create a
+	// JoinClause, or NewRawJoinClause for the joins they cannot express. The
+	// dialect of the given join clause should be the same dialect of the
+	// Paginator. Join clauses will be added to the sql command in the same
+	// order in which they were given.
+	AddJoinClause(clause JoinClause) error
+
+	// AddConditionalJoinClause adds a custom join clause like AddJoinClause, but
+	// the join clause will be added to the sql command only when the joined table
+	// is referenced, e.g. as "developer.programming_language", in any of the where
+	// clauses, having clauses or custom "ORDER BY" clauses of the Paginator. This
+	// avoids unnecessary joins when a request does not need them. Note that an
+	// omitted inner join will not filter out the records without a match in the
+	// joined table, so conditional joins are usually left joins.
+	AddConditionalJoinClause(clause JoinClause) error
+
+	// AppliedFilters returns the filters of the request url that Paginator applies
+	// to the records, in the order of the columns of the table. Filters given with
+	// AddWhereClause are not included. For example, given a request url like:
+	//
+	//	http://localhost/employees?salary>=4000&salary<=8000&name=~ringo
+	//
+	// the applied filters will be ``salary >= 4000``, ``salary <= 8000`` and
+	// ``name LIKE ringo``. Use this, for example, to show the active filters.
+	AppliedFilters() []AppliedFilter
+
+	// SetFilterConjunction sets the conjunction ("AND" or "OR") that will be used
+	// to combine the filters given in the request url. By default the filters are
+	// combined with AND. When using OR the filters will be grouped together, so the
+	// where clauses added with AddWhereClause will still be combined with AND, e.g.:
+	//
+	//	WHERE (name = $1 OR last_name = $2) AND (tenant_id = $3)
+	SetFilterConjunction(conjunction string) error
 }
 
 // paginator is the concrete type that implements the Paginator interface.
@@ -117,10 +339,20 @@ type paginator struct {
 	// package which will be executed in the helper createWhereClause.
 	predicates []RawWhereClause
 
+	// strictRawClauses indicates whether the identifiers referenced by the
+	// predicates added with AddWhereClause should be checked against cols and
+	// rawClauseTables. See the StrictRawClauses option.
+	strictRawClauses bool
+
+	// rawClauseTables holds the tables, e.g. joined tables, whose columns can
+	// be referenced by the predicates added with AddWhereClause under
+	// strictRawClauses.
+	rawClauseTables []string
+
 	// joins holds custom join clauses created by the user of this
 	// package which will be added to the generated sql query in
-	// Paginator.Paginate.
-	joins []string
+	// Paginator.Paginate in insertion order.
+	joins []joinClause
 
 	// stop is used by NextData and Scan. Scan will set the value of stop
 	// to true whenever Scan returns an error. This will allow NextData to
@@ -139,6 +371,15 @@ type paginator struct {
 	// deterministic when paginating the data.
 	id string
 
+	// columnNamer infers the column names of the fields without the tag "col"
+	// when it is not nil. See the ColumnNamer option.
+	columnNamer func(fieldName string) string
+
+	// ids holds the columns of all the fields tagged with "id" in declaration
+	// order, e.g. for tables with a composite primary key. The records will be
+	// sorted by all of them. The first one is id.
+	ids []string
+
 	// cols holds the names of the columns of the database table.
 	// This package will infer the column names from the struct `fields`
 	// of the given table and it will convert any camel case field name
@@ -161,6 +402,10 @@ type paginator struct {
 	// better understanding.
 	rows []interface{}
 
+	// rawRows holds the values of each row in rows in column order with
+	// the nullable values normalized. See ScanRows.
+	rawRows [][]interface{}
+
 	// tmp holds the values for each row in rows. tmp will hold the values
 	// temporarily everytime we run GetRowPtrArgs. We use these values to
 	// scan with the Scan method from the go sql package the paginated data.
@@ -175,6 +420,17 @@ type paginator struct {
 	// to the value defined by defaultPageSize.
 	pageSize int
 
+	// maxPageSize and maxOffset are the safety limits of the page size and of
+	// the offset. See the MaxPageSize and MaxOffset options.
+	maxPageSize  int
+	maxOffset    int
+	hasMaxOffset bool
+
+	// pageSizeClamped and offsetClamped indicate whether the page size or the
+	// page number given in the request were reduced by the safety limits.
+	pageSizeClamped bool
+	offsetClamped   bool
+
 	// pageNumber represents the "number" of the page that the end user wants
 	// to see for the paginated data. We will get this value from the request url values
 	// if given. Otherwise, we will fallback to value defined by defaultPageNumber.
@@ -184,6 +440,10 @@ type paginator struct {
 	// the database.
 	totalSize int
 
+	// countColumn is the index in tmp of the count(*) over() column scanned into
+	// totalSize or -1 if the total size is fetched with a separate count query.
+	countColumn int
+
 	// pageCount represents the total number of records retrieved by paginator
 	// from the database.
 	pageCount int
@@ -217,261 +477,1381 @@ type paginator struct {
 	// orderByClauses holds custom "ORDER BY" clauses that will be added to the generated
 	// sql command. See createOrderByClause.
 	orderByClauses customOrderByClauses
+
+	// defaultOrderByClauses holds the "ORDER BY" clauses given with the
+	// DefaultOrderBy option, which will be added to p.orderByClauses only
+	// when the request does not have sort directives.
+	defaultOrderByClauses customOrderByClauses
+
+	// lastModifiedColumn is the name of the timestamp column used to compute
+	// lastModified. See the LastModifiedColumn option.
+	lastModifiedColumn string
+
+	// lastModified holds the most recent timestamp of the lastModifiedColumn
+	// among the rows added by addRow. hasLastModified will be true once
+	// lastModified holds a valid timestamp.
+	lastModified    time.Time
+	hasLastModified bool
+
+	// nullsLastByDefault indicates whether the NULL values of the nullable
+	// columns should be sorted last. See the NullsLastByDefault option.
+	nullsLastByDefault bool
+
+	// nullsFirst holds the names of the nullable columns whose NULL values
+	// should be sorted first. See the NullsFirst option.
+	nullsFirst []string
+
+	// searchParam is the name of the request parameter holding the search terms
+	// that will be matched against searchColumns. See the Search option.
+	searchParam string
+
+	// searchColumns holds the names of the columns that will be searched with the
+	// terms given in the searchParam request parameter. See the Search option.
+	searchColumns []string
+
+	// randomSeed is the seed used to sort the records in a reproducible
+	// pseudo-random order when seededRandom is true. See the
+	// OrderBySeededRandom option.
+	randomSeed   int64
+	seededRandom bool
+
+	// fullTextSearchParam is the name of the request parameter holding the
+	// full-text search query that will be matched against the columns of
+	// fullTextSearchWeights. See the FullTextSearch option.
+	fullTextSearchParam string
+
+	// fullTextSearchWeights maps the names of the columns used in the
+	// full-text search with their postgres weights (A, B, C or D).
+	fullTextSearchWeights map[string]string
+
+	// fullTextSearchPlain indicates whether the full-text search query is
+	// parsed with plainto_tsquery instead of websearch_to_tsquery. See the
+	// PlainFullTextSearch option.
+	fullTextSearchPlain bool
+
+	// strictPaging indicates whether invalid page numbers in the request
+	// should be rejected instead of clamped. See the StrictPaging option.
+	strictPaging bool
+
+	// softDeleteColumn is the name of the column that marks the soft deleted
+	// records of the table, and includeDeleted indicates whether these records
+	// should be paginated anyway. See the SoftDelete and IncludeDeleted options.
+	softDeleteColumn string
+	includeDeleted   bool
+
+	// emptyAsNull indicates whether the filter parameters with empty values
+	// should be interpreted as IS NULL clauses instead of being ignored.
+	// See the EmptyAsNull option.
+	emptyAsNull bool
+
+	// separateCount indicates whether the total number of records should be
+	// fetched with a separate count query instead of the count(*) over() window
+	// function. It is true for the mariadb dialect. See Paginator.Execute.
+	separateCount bool
+
+	// placeholders is the style of the placeholders of the sql commands, e.g.
+	// $1, $2, etc. for postgres. See the PlaceholderStyle option.
+	placeholders placeholderStyle
+
+	// concurrentCount indicates whether the separate count query should run
+	// concurrently with the query of the page. See the ConcurrentCount option.
+	concurrentCount bool
+
+	// pageParam and pageSizeParam are the names of the request parameters
+	// holding the page number and the page size. See the PageParam and
+	// PageSizeParam options.
+	pageParam     string
+	pageSizeParam string
+
+	// limitOffset indicates whether the page size and the number of records
+	// to skip can be given with the ``limit`` and ``offset`` request parameters.
+	// See the LimitOffset option.
+	limitOffset bool
+
+	// offset is the number of records to skip given directly in the request
+	// when hasOffset is true. Otherwise the number of records to skip is
+	// computed from pageNumber and pageSize. See currentOffset.
+	offset    int
+	hasOffset bool
+
+	// projection holds the names of the fields or columns given with the
+	// Columns option.
+	projection []string
+
+	// excluded holds the names of the fields or columns given with the
+	// ExcludeColumns option.
+	excluded []string
+
+	// fieldsParam is the name of the request parameter holding the fields that
+	// should be selected, e.g. ``fields=name,last_name``, or excluded from the
+	// selection, e.g. ``fields=-notes,-metadata``. See the FieldsParam option.
+	fieldsParam string
+
+	// requestFields and requestExcluded hold the fields given in the request
+	// parameter fieldsParam that should be selected or excluded.
+	requestFields   []string
+	requestExcluded []string
+
+	// ignoreUnknownFields indicates whether the fields given in the request
+	// parameter fieldsParam that are not fields or columns of the table should
+	// be ignored instead of rejected. See the AllowSparseFields option.
+	ignoreUnknownFields bool
+
+	// selectedCols and selectedFields hold the columns that will be selected in
+	// the sql command and the names of their fields in the given table. Unless
+	// the Columns option is used, they are the same as cols and fields.
+	selectedCols   []string
+	selectedFields []string
+
+	// groupBy holds the columns of the sql GROUP BY clause. See the GroupBy option.
+	groupBy []string
+
+	// quoteIdentifiers indicates whether the names of the table and its columns
+	// should be quoted in the sql commands. See the QuoteIdentifiers option.
+	quoteIdentifiers bool
+
+	// onQuery is called with every sql command run by Paginator with a Querier.
+	// See the OnQuery option.
+	onQuery func(sql string, args []interface{}, elapsed time.Duration)
+
+	// distinct indicates whether only distinct records should be selected.
+	// See the Distinct option.
+	distinct bool
+
+	// matchesNothing is true when the where clause of the last built sql
+	// command can never match a record, so the queries can be skipped.
+	matchesNothing bool
+
+	// havingPredicates holds the custom raw having clauses added
+	// with AddHavingClause.
+	havingPredicates []RawHavingClause
+
+	// sortParam is the name of the request parameter holding the
+	// sort directives. See the SortParam option.
+	sortParam string
+
+	// location is the time zone of the time columns. See the Location option.
+	location *time.Location
+
+	// timeLayout is the layout of the timestamps given in the request to
+	// filter the time columns. See the TimeLayout option.
+	timeLayout string
+
+	// functions maps the names of the columns of the fields with the tag "fn"
+	// with the sql function that should be applied to the columns when filtering,
+	// e.g. "date_joined" => "DATE" will filter with DATE(date_joined) = $1.
+	functions map[string]string
+
+	// jsonColumns holds the names of the filter columns of the fields with the
+	// tag "json", which can be filtered by the keys of their json values, e.g.
+	// ``meta.role=admin`` will filter with meta->>'role' = $1.
+	jsonColumns []string
+
+	// arrayColumns holds the names of the filter columns of the fields with the
+	// tag "array", which are filtered by the elements of their array values, e.g.
+	// ``tags=go`` will filter with $1 = ANY(tags).
+	arrayColumns []string
+
+	// noSelectColumns holds the names of the columns of the fields with the tag
+	// "noselect", which can be filtered and sorted but are never selected.
+	noSelectColumns []string
+
+	// filterConjunction is the conjunction used to combine the filters
+	// of the request url. See Paginator.SetFilterConjunction.
+	filterConjunction string
+
+	// cursorParam is the name of the request parameter holding the continuation
+	// token used for keyset pagination. See the KeysetPagination option.
+	cursorParam string
+
+	// keysetClauses holds the "ORDER BY" clauses whose values are encoded in the
+	// continuation tokens, in the same order as in the query.
+	keysetClauses []orderByClause
+
+	// scanned indicates whether the rows of the query have been scanned
+	// completely, that is, whether Execute succeeded or NextData or ScanRows
+	// have been called. See Response.
+	scanned bool
+
+	// consumed holds the number of rows scanned by Scan or ScanRows.
+	// See Paginator.Consumed.
+	consumed int
+
+	// mu guards the state of the scanning operations, i.e. rows, rawRows,
+	// tmp, closed and stop, so NextData, Scan, ScanMap, ScanRows, GetRowPtrArgs,
+	// Response, LastModified, Reset and SetPage can be called from several
+	// goroutines.
+	mu sync.Mutex
+
+	// lastRow holds the last row added by addRow. It is used to create the
+	// continuation token of the next page.
+	lastRow interface{}
 }
 
 func (p *paginator) Paginate() (sql string, values []interface{}, err error) {
+	return p.createQuery(p.selection())
+}
+
+// selection returns the columns of the table and the total count of
+// records that the sql command created by Paginate will select.
+func (p *paginator) selection() string {
+	// If there are join clauses we need to qualify the columns of the paginated
+	// table with its name to avoid clashes with the columns of the joined tables.
+	cols := p.selectedCols
+	if len(p.activeJoins()) > 0 || p.quoteIdentifiers {
+		cols = make([]string, 0, len(p.selectedCols))
+		for _, c := range p.selectedCols {
+			cols = append(cols, p.column(c))
+		}
+	}
+
+	selection := strings.Join(cols, ", ")
+	if p.distinct {
+		selection = "DISTINCT " + selection
+	}
+
+	if p.separateCount {
+		return selection
+	}
+	return selection + ", count(*) over()"
+}
+
+// createCountQuery creates the sql command with the corresponding arguments to
+// count the total number of records of the table matching the filters. It is used
+// instead of the count(*) over() window function when separateCount is true.
+func (p *paginator) createCountQuery() (string, []interface{}, error) {
+	if err := p.validateFilters(); err != nil {
+		return "", nil, err
+	}
+
+	c := make(chan whereClause)
+	go createWhereClause(p.dialect, p.filterTable(), p.cols, p.parameters, p.functions, p.converters(), p.filterConjunction, p.where(), p.quoteIdentifiers, c)
+	where := <-c
+
+	p.matchesNothing = where.matchesNothing
+
+	joins, joinArgs := p.joinsClause()
+	sqlStr := "SELECT count(*) FROM " + p.fromTable() + joins
+
+	if where.exists {
+		sqlStr += where.clause
+	}
+
+	// Under GROUP BY we need to count the groups instead of the records.
+	having, havingArgs := p.havingClause()
+	if len(p.groupBy) > 0 || p.distinct || having != "" {
+		sqlStr = "SELECT count(*) FROM (SELECT 1" + strings.TrimPrefix(sqlStr, "SELECT count(*)") + p.groupByClause() + having + ") AS grouped"
+	}
+
+	args := append(joinArgs, where.args...)
+	args = append(args, havingArgs...)
+
+	return renderPlaceholders(sqlStr, p.dialect, p.placeholders), args, nil
+}
+
+// queryTotalSize sets p.totalSize with the result of the count query.
+func (p *paginator) queryTotalSize(ctx context.Context, q Querier) error {
+	totalSize, err := p.queryCount(ctx, q)
+	if err != nil {
+		return err
+	}
+	p.totalSize = totalSize
+	return nil
+}
+
+// queryCount returns the result of the count query.
+func (p *paginator) queryCount(ctx context.Context, q Querier) (count int, err error) {
+	cmd, args, err := p.createCountQuery()
+	if err != nil {
+		return 0, err
+	}
+
+	// There is no need to hit the database when the filters cannot match any record.
+	if p.matchesNothing {
+		return 0, nil
+	}
+
+	rows, err := q.QueryContext(ctx, cmd, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		if err = rows.Scan(&count); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, rows.Err()
+}
+
+func (p *paginator) Count(ctx context.Context, q Querier) (int, error) {
+	if q == nil {
+		return 0, fmt.Errorf("paginate: cannot pass nil as querier")
+	}
+	q = p.observe(q)
+
+	return p.queryCount(ctx, q)
+}
+
+// createQuery creates the sql command with the corresponding arguments to paginate
+// the table, selecting the given selection (e.g. "id, name").
+func (p *paginator) createQuery(selection string) (string, []interface{}, error) {
+	return p.createQueryWithPagination(selection, false)
+}
+
+// createQueryWithPagination is like createQuery, but when parameterized is true the
+// LIMIT and OFFSET values of the sql command will be placeholders, so the command
+// can be prepared once and executed for any page. In that case the LIMIT and OFFSET
+// arguments are not included in the returned arguments and should be given last
+// when executing the command.
+func (p *paginator) createQueryWithPagination(selection string, parameterized bool) (string, []interface{}, error) {
+	sqlStr, args, err := p.buildQuery(selection, parameterized)
+	if err != nil {
+		return "", nil, err
+	}
+	return renderPlaceholders(sqlStr, p.dialect, p.placeholders), args, nil
+}
+
+// buildQuery is like createQueryWithPagination but the placeholders of the
+// returned sql command are not rendered with the style of the Paginator, so
+// all of them are question marks. See renderPlaceholders.
+func (p *paginator) buildQuery(selection string, parameterized bool) (string, []interface{}, error) {
+	if err := p.validateFilters(); err != nil {
+		return "", nil, err
+	}
+
 	var sqlStr string
 	c1 := make(chan whereClause)
 	c2 := make(chan string)
 	c3 := make(chan string)
-	go createWhereClause(p.dialect, p.cols, p.parameters, p.predicates, c1)
-	go createPaginationClause(p.pageNumber, p.pageSize, c2)
-	go createOrderByClause(p.parameters, p.cols, p.orderByClauses, p.id, c3)
+	go createWhereClause(p.dialect, p.filterTable(), p.cols, p.parameters, p.functions, p.converters(), p.filterConjunction, p.where(), p.quoteIdentifiers, c1)
+	if parameterized {
+		go createParameterizedPaginationClause(p.dialect, c2)
+	} else if p.hasOffset {
+		go createOffsetPaginationClause(p.offset, p.pageSize, c2)
+	} else {
+		go createPaginationClause(p.pageNumber, p.pageSize, c2)
+	}
+	go createOrderByClause(p.dialect, p.parameters, p.sortParam, p.cols, p.orderBy(), p.ids, p.nullsOrdering(), p.quoteIdentifiers, c3)
 	where := <-c1
 	pagination := <-c2
 	order := <-c3
-
-	sqlStr = "SELECT " + strings.Join(p.cols, ", ") + ", count(*) over() FROM " + p.name
+	p.matchesNothing = where.matchesNothing
 
 	// If there are custom join clauses we need to add them in the sql query string.
-	if len(p.joins) > 0 {
-		sqlStr += " " + strings.Join(p.joins, " ")
-	}
+	joins, joinArgs := p.joinsClause()
+	sqlStr = "SELECT " + selection + " FROM " + p.fromTable() + joins
 
 	if where.exists {
-		sqlStr += where.clause + order + pagination
-		// As an special case we need to enumerate the placeholders if users are using
-		// postgres. See, for example, the documentation of this postgres driver library:
-		// https://pkg.go.dev/github.com/lib/pq#section-documentation
-		if p.dialect == "postgres" {
-			numArgs := len(where.args)
-			placeholders := make([]interface{}, 0)
-			for i := 1; i < numArgs+1; i++ {
-				placeholders = append(placeholders, i)
-			}
-			sqlStr = fmt.Sprintf(sqlStr, placeholders...)
-		}
-	} else {
-		sqlStr += order + pagination
+		sqlStr += where.clause
 	}
-	return sqlStr, where.args, nil
+	having, havingArgs := p.havingClause()
+	sqlStr += p.groupByClause() + having + order + pagination
+
+	// The arguments should follow the same order of the placeholders in the
+	// sql command: first the arguments of the join clauses, then the arguments
+	// of the where clause, then the arguments of the having clause and then the
+	// arguments of the custom "ORDER BY" clauses.
+	args := joinArgs
+	args = append(args, where.args...)
+	args = append(args, havingArgs...)
+	args = append(args, p.orderByClauses.args()...)
+
+	return sqlStr, args, nil
 }
 
-func (p *paginator) Response() PaginationResponse {
-	p.response.PageNumber = p.pageNumber
-	p.response.PageCount = p.pageCount
-	p.response.TotalSize = p.totalSize
+// sqlFunctionRegexp matches the valid names of the sql functions given with the tag "fn".
+var sqlFunctionRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
-	if (p.pageNumber * p.pageSize) < p.totalSize {
-		p.response.NextPageNumber = p.pageNumber + 1
-		p.response.HasNextPage = true
-	}
-	if (p.pageNumber * p.pageSize) == p.totalSize {
-		p.response.NextPageNumber = 0
-		p.response.HasNextPage = false
-	}
-	if p.totalSize == 0 {
-		p.response.NextPageNumber = 0
-		p.response.HasNextPage = false
+// columnNameRegexp matches the valid column names given with the tag "col", which
+// can be qualified with a table name, e.g. "developer.programming_language".
+var columnNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// paramNameRegexp matches the valid request parameter names given with the tag
+// "param", which cannot contain the operators of the filters, e.g. "=" or "<".
+var paramNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// paginationLiteralsRegexp is used by QueryShape to normalize the
+// literal values of the pagination clause of the sql command.
+var paginationLiteralsRegexp = regexp.MustCompile(`LIMIT [0-9]+ OFFSET [0-9]+`)
+
+func (p *paginator) QueryShape() string {
+	cmd, _, err := p.buildQuery(p.selection(), false)
+	if err != nil {
+		return ""
 	}
-	if p.response.PageNumber > 1 {
-		p.response.HasPreviousPage = true
+	return paginationLiteralsRegexp.ReplaceAllString(cmd, "LIMIT ? OFFSET ?")
+}
+
+func (p *paginator) DebugSQL() string {
+	cmd, args, err := p.buildQuery(p.selection(), false)
+	if err != nil {
+		return ""
 	}
 
-	return p.response
+	return replacePlaceholders(cmd, p.dialect, func(n int) string {
+		if n <= len(args) {
+			return sqlLiteral(args[n-1], p.dialect)
+		}
+		return _placeholder
+	})
 }
 
-// validateTable validates if the given table struct is valid.
-func (p *paginator) validateTable() error {
-	if p.rv.Type().Kind() != reflect.Struct {
-		return fmt.Errorf("paginate: table should be of struct type")
+func (p *paginator) IDs(ctx context.Context, q Querier) ([]interface{}, error) {
+	if q == nil {
+		return nil, fmt.Errorf("paginate: cannot pass nil as querier")
 	}
+	q = p.observe(q)
 
-	if !p.rv.IsZero() {
-		return fmt.Errorf("paginate: table struct should be empty with only the default zero values")
+	if len(p.ids) > 1 {
+		return nil, fmt.Errorf("paginate: IDs cannot be used with the composite id %v of table %s", p.ids, p.name)
 	}
 
-	numOfIDs := 0
+	id := p.column(p.id)
 
-	// See usage below.
-	countIDs := func(tags []string) int {
-		s := "id"
-		c := 0
-		for _, tag := range tags {
-			if tag == s {
-				c++
-			}
-		}
-		return c
+	cmd, args, err := p.createQuery(id)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < p.rv.NumField(); i++ {
-		field := p.rv.Type().Field(i)
-		tags := strings.Split(field.Tag.Get("paginate"), ";")
-		numOfIDs += countIDs(tags)
-		fieldName := field.Name
-		T := reflect.Indirect(p.rv).FieldByName(fieldName).Interface()
-		switch T.(type) {
-		case string:
-			continue
-		case int, int8, int16, int32, int64:
-			continue
-		case bool:
-			continue
-		case float32, float64:
-			continue
-		case time.Time:
-			continue
-		case NullInt, NullBool, NullString, NullTime, NullFloat64:
-			continue
-		default:
-			return fmt.Errorf("paginate: invalid type for field %q", fieldName)
-		}
+	if p.matchesNothing {
+		return []interface{}{}, nil
 	}
 
-	if numOfIDs == 0 {
-		return fmt.Errorf("paginate: id has not been defined in any " +
-			"of the field of the given struct")
-	} else if numOfIDs > 1 {
-		return fmt.Errorf("paginate: more than one id has beend defined " +
-			"in the fields of the given struct")
+	rows, err := q.QueryContext(ctx, cmd, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return nil
-}
-
-// getColsAndMapParameters does two things:
-//
-// (1) It infers the column names of the database table from the given ``table``
-//     struct fields. If the fields have the tag "col", the column name will be taken
-//     from there.
-// (2) It will map the column names with request ``parameter`` names if the struct
-//     fields have the tag "param" on it.
-//
-// Malformed "col" and "param" tags will be ignored silently.
-func (p *paginator) getColsAndMapParameters() {
+	// We will scan the ids with the type of the id field of the given table.
+	idType := p.rv.FieldByName(p.fields[indexOf(p.id, p.cols)]).Type()
 
-	getColNameFromTags := func(tags []string) (hasColTag bool, colName string) {
-		for _, tag := range tags {
-			kv := strings.Split(tag, "=")
-			if len(kv) != 2 {
-				continue
-			}
-			k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
-			if k != col {
-				continue
-			}
-			return true, v
+	ids := make([]interface{}, 0)
+	for rows.Next() {
+		v := reflect.New(idType)
+		if err = rows.Scan(v.Interface()); err != nil {
+			return nil, err
 		}
-		return hasColTag, colName
+		ids = append(ids, v.Elem().Interface())
 	}
 
-	getParamFromTags := func(tags []string) (hasParamTag bool, paramName string) {
-		for _, tag := range tags {
-			kv := strings.Split(tag, "=")
-			if len(kv) != 2 {
-				continue
-			}
-			k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
-			if k != param {
-				continue
-			}
-			return true, v
-		}
-		return hasParamTag, paramName
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < p.rv.NumField(); i++ {
-		field := p.rv.Type().Field(i)
-		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
-		if hasColTag, name := getColNameFromTags(tags); hasColTag {
-			p.cols = append(p.cols, name)
-			if hasParamTag, paramName := getParamFromTags(tags); hasParamTag {
-				p.mappers.Add(name, paramName)
-			}
-			continue
-		}
-		fieldName := field.Name
-		sneakName := parseCamelCaseToSnakeLowerCase(fieldName)
-		if hasParamTag, paramName := getParamFromTags(tags); hasParamTag {
-			p.mappers.Add(sneakName, paramName)
-		}
-		p.cols = append(p.cols, sneakName)
+	return ids, nil
+}
+
+// observe returns the given Querier wrapped with an observedQuerier calling
+// p.onQuery, or the given Querier itself if the OnQuery option was not used.
+func (p *paginator) observe(q Querier) Querier {
+	if p.onQuery == nil {
+		return q
 	}
+	return observedQuerier{q: q, hook: p.onQuery}
 }
 
-func (p *paginator) getFieldNames() {
-	for i := 0; i < p.rv.NumField(); i++ {
-		field := p.rv.Type().Field(i)
-		fieldName := field.Name
-		p.fields = append(p.fields, fieldName)
+// filterTable returns the name of the table that should qualify the filtered
+// columns in the where clause, i.e. p.name if there are active join clauses,
+// or an empty string otherwise. See createWhereClause.
+func (p *paginator) filterTable() string {
+	if len(p.activeJoins()) > 0 {
+		return p.name
 	}
+	return ""
 }
 
-func (p *paginator) getFilters() {
+// fromTable returns the name of the table for the FROM clause of the sql
+// commands, quoted if the QuoteIdentifiers option is used.
+func (p *paginator) fromTable() string {
+	if p.quoteIdentifiers {
+		return quoteIdentifier(p.dialect, p.name)
+	}
+	return p.name
+}
 
-	hasfilter := func(tags []string) bool {
-		for _, tag := range tags {
-			if tag == filter {
-				return true
-			}
+// column returns the given column of the table for the sql commands, qualified
+// with the name of the table if there are active join clauses, and quoted if the
+// QuoteIdentifiers option is used, e.g. "employees"."order".
+func (p *paginator) column(c string) string {
+	if len(p.activeJoins()) > 0 {
+		c = qualifyColumn(p.name, c)
+	}
+	if p.quoteIdentifiers {
+		c = quoteIdentifier(p.dialect, c)
+	}
+	return c
+}
+
+// orderBy returns p.orderByClauses with the columns of the CASE expressions of
+// OrderByCase, and of the other expressions created by this package, qualified
+// and quoted like the rest of the columns, see column.
+func (p *paginator) orderBy() customOrderByClauses {
+	clauses := make(customOrderByClauses, len(p.orderByClauses))
+	for i, clause := range p.orderByClauses {
+		if clause.caseColumn != "" {
+			clause.column = createCaseExpression(p.column(clause.caseColumn), len(clause.args))
 		}
-		return false
+		if clause.renderColumn != nil {
+			clause.column = clause.renderColumn(p.column)
+		}
+		clauses[i] = clause
 	}
+	return clauses
+}
 
-	for i := 0; i < p.rv.NumField(); i++ {
-		field := p.rv.Type().Field(i)
-		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
-		if !hasfilter(tags) {
-			continue
+// where returns p.predicates with the predicates created by this package
+// rendered again, so their columns are qualified and quoted like the rest of
+// the columns, see column.
+func (p *paginator) where() []RawWhereClause {
+	predicates := make([]RawWhereClause, len(p.predicates))
+	for i, predicate := range p.predicates {
+		if predicate.render != nil {
+			predicate.predicate = predicate.render(p.column)
 		}
-		sneakName := parseCamelCaseToSnakeLowerCase(field.Name)
-		p.filters = append(p.filters, sneakName)
+		predicates[i] = predicate
 	}
+	return predicates
 }
 
-func (p *paginator) getID() {
-	id := ""
+// nullableColumns returns the columns of the table whose fields can hold NULL
+// values. See isNullableField.
+func (p *paginator) nullableColumns() []string {
+	cols := make([]string, 0)
+	for i, c := range p.cols {
+		if isNullableField(p.rv.FieldByName(p.fields[i]).Type()) {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
 
-	hasID := func(tags []string) bool {
-		for _, tag := range tags {
-			if tag == "id" {
-				return true
-			}
+// rangeColumns returns the columns of the table that can be filtered with
+// ranges like ``4000..8000``. See isRangeType.
+func (p *paginator) rangeColumns() []string {
+	cols := make([]string, 0)
+	for i, c := range p.cols {
+		if isRangeType(p.rv.FieldByName(p.fields[i]).Type()) {
+			cols = append(cols, c)
 		}
-		return false
 	}
+	return cols
+}
 
-	for i := 0; i < p.rv.NumField(); i++ {
-		field := p.rv.Type().Field(i)
-		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
-		if hasID(tags) {
-			id = parseCamelCaseToSnakeLowerCase(field.Name)
-			break
+// nullsOrdering returns the placement of the NULL values ("FIRST" or "LAST")
+// of the nullable columns of the table when sorting, see isNullableField. See the
+// NullsLastByDefault and NullsFirst options.
+func (p *paginator) nullsOrdering() map[string]string {
+	nulls := make(map[string]string)
+	for i, c := range p.cols {
+		if !isNullableField(p.rv.FieldByName(p.fields[i]).Type()) {
+			continue
+		}
+		if isStringIn(c, p.nullsFirst) {
+			nulls[c] = "FIRST"
+		} else if p.nullsLastByDefault {
+			nulls[c] = "LAST"
 		}
 	}
+	return nulls
+}
 
-	p.id = id
+func (p *paginator) Execute(ctx context.Context, q Querier) error {
+	return p.execute(ctx, q, p.scanRows)
 }
 
-func (p *paginator) getTableName() {
-	name := parseCamelCaseToSnakeLowerCase(p.rv.Type().Name())
-	p.name = name
+func (p *paginator) ForEach(ctx context.Context, q Querier, fn func(row interface{}) error) error {
+	if fn == nil {
+		return fmt.Errorf("paginate: cannot pass nil as callback")
+	}
+	return p.execute(ctx, q, func(rows *sql.Rows) error {
+		return p.streamRows(rows, fn)
+	})
 }
 
-func (p *paginator) GetRowPtrArgs() []interface{} {
-	if p.started {
+// execute runs the sql command created by Paginate with the given Querier,
+// and the count query if needed, and reads the returned rows with scan.
+func (p *paginator) execute(ctx context.Context, q Querier, scan func(rows *sql.Rows) error) error {
+	if q == nil {
+		return fmt.Errorf("paginate: cannot pass nil as querier")
+	}
+	q = p.observe(q)
+
+	cmd, args, err := p.Paginate()
+	if err != nil {
+		return err
+	}
+
+	// There is no need to hit the database when the filters cannot match
+	// any record, the page will be empty and the total size zero.
+	if p.matchesNothing {
+		p.totalSize = 0
+		p.scanned = true
 		return nil
 	}
-	if len(p.tmp) > 0 {
-		p.addRow()
+
+	if p.separateCount && p.concurrentCount {
+		return p.executeConcurrently(ctx, q, cmd, args, scan)
 	}
-	for _, fieldName := range p.fields {
-		I := reflect.Indirect(p.rv).FieldByName(fieldName).Interface()
-		switch I.(type) {
-		case NullInt:
+
+	if p.separateCount {
+		if err = p.queryTotalSize(ctx, q); err != nil {
+			return err
+		}
+	}
+
+	rows, err := q.QueryContext(ctx, cmd, args...)
+	if err != nil {
+		return err
+	}
+	return scan(rows)
+}
+
+// executeConcurrently runs the count query in a new goroutine while it runs
+// the given sql command of the page and reads its rows with scan. If any of the
+// queries fails the context of the other one is cancelled and the first error
+// is returned, not the cancellation error of the other query.
+func (p *paginator) executeConcurrently(ctx context.Context, q Querier, cmd string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := p.queryTotalSize(ctx, q); err != nil {
+			fail(err)
+		}
+	}()
+
+	rows, err := q.QueryContext(ctx, cmd, args...)
+	if err == nil {
+		err = scan(rows)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	<-done
+	return firstErr
+}
+
+// scanRows scans all the given rows with GetRowPtrArgs and closes them.
+func (p *paginator) scanRows(rows *sql.Rows) (err error) {
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		if err = rows.Scan(p.GetRowPtrArgs()...); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	p.scanned = true
+	return nil
+}
+
+// streamRows scans all the given rows like scanRows and closes them, but every
+// row is given to fn as soon as it is scanned instead of being added to p.rows,
+// so the rows of the page are not held in memory. The iteration stops at the
+// first error returned by fn.
+func (p *paginator) streamRows(rows *sql.Rows, fn func(row interface{}) error) (err error) {
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		if err = rows.Scan(p.GetRowPtrArgs()...); err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		row := p.newRow(p.fieldValues())
+		// The pointers of p.tmp have been read, so its backing
+		// array can be reused by the next call to GetRowPtrArgs.
+		p.tmp = p.tmp[:0]
+		p.pageCount++
+		p.consumed++
+		p.mu.Unlock()
+
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// There are no rows left for NextData and Scan, and the page
+	// count must not be reset by finalize.
+	p.once.Do(func() { p.started = true })
+	p.closed = true
+	p.scanned = true
+	return nil
+}
+
+func (p *paginator) Prepare(ctx context.Context, db Preparer) (*PreparedPaginator, error) {
+	if db == nil {
+		return nil, fmt.Errorf("paginate: cannot pass nil as preparer")
+	}
+
+	cmd, args, err := p.createQueryWithPagination(p.selection(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := db.PrepareContext(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared := &PreparedPaginator{p: p, stmt: stmt, args: args}
+
+	if p.separateCount {
+		countCmd, countArgs, err := p.createCountQuery()
+		if err != nil {
+			stmt.Close()
+			return nil, err
+		}
+		prepared.countStmt, err = db.PrepareContext(ctx, countCmd)
+		if err != nil {
+			stmt.Close()
+			return nil, err
+		}
+		prepared.countArgs = countArgs
+	}
+
+	return prepared, nil
+}
+
+// reset clears the scanned rows and the state of the scanning
+// operations, so the paginator can scan the rows of another page.
+func (p *paginator) reset() {
+	p.rows = nil
+	p.rawRows = nil
+	p.tmp = make([]interface{}, 0)
+	p.closed = false
+	p.started = false
+	p.stop = false
+	p.once = sync.Once{}
+	p.totalSize = 0
+	p.pageCount = 0
+	p.response = PaginationResponse{}
+	p.lastModified = time.Time{}
+	p.hasLastModified = false
+	p.lastRow = nil
+	p.scanned = false
+	p.consumed = 0
+}
+
+func (p *paginator) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reset()
+}
+
+func (p *paginator) SetPage(n int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if n <= 0 {
+		if p.strictPaging {
+			return fmt.Errorf("paginate: page should be greater than zero; got %d", n)
+		}
+		n = defaultPageNumber
+	}
+	previous, hadOffset := p.pageNumber, p.hasOffset
+	p.pageNumber = n
+	p.hasOffset = false
+	if err := p.clampPageNumber(); err != nil {
+		p.pageNumber, p.hasOffset = previous, hadOffset
+		return err
+	}
+	return nil
+}
+
+func (p *paginator) Response() PaginationResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The page count is known once the rows are fetched, even if
+	// they are not consumed with Scan, e.g. when there are none.
+	if p.scanned {
+		p.finalize()
+	}
+
+	p.response.PageNumber = p.pageNumber
+	p.response.PageSize = p.pageSize
+	p.response.PageSizeClamped = p.pageSizeClamped
+	p.response.OffsetClamped = p.offsetClamped
+	p.response.Partial = !p.scanned
+
+	// The counts of a partial response are not reliable, e.g. the total size
+	// could come from the first row of a page that failed to be scanned.
+	pageCount, totalSize := p.pageCount, p.totalSize
+	if p.response.Partial {
+		pageCount, totalSize = 0, 0
+	}
+	p.response.PageCount = pageCount
+	p.response.TotalSize = totalSize
+	p.response.TotalPages = getTotalPages(totalSize, p.pageSize)
+
+	p.response.Offset = p.currentOffset()
+
+	p.response.HasNextPage = p.pageNumber < p.response.TotalPages
+	p.response.HasPreviousPage = p.pageNumber > 1
+	if p.hasOffset {
+		p.response.HasNextPage = p.offset+p.pageSize < totalSize
+		p.response.HasPreviousPage = p.offset > 0
+	}
+	p.response.NextPageNumber = 0
+	if p.response.HasNextPage {
+		p.response.NextPageNumber = p.pageNumber + 1
+	}
+
+	if p.cursorParam != "" {
+		p.response.NextPageNumber = 0
+		p.response.HasNextPage = totalSize > pageCount
+		p.response.NextCursor = ""
+		if p.response.HasNextPage && p.lastRow != nil {
+			p.response.NextCursor = p.nextCursor()
+		}
+	}
+
+	return p.response
+}
+
+// validateTable validates if the given table struct is valid.
+func (p *paginator) validateTable() error {
+	if p.rv.Type().Kind() != reflect.Struct {
+		return fmt.Errorf("paginate: table should be of struct type")
+	}
+
+	if !p.rv.IsZero() {
+		return fmt.Errorf("paginate: table struct should be empty with only the default zero values")
+	}
+
+	return getTableMetadata(p.rv.Type()).err
+}
+
+// validateFields validates if the fields of the given table struct are valid.
+func (p *paginator) validateFields() error {
+	numOfIDs := 0
+
+	// See usage below.
+	countIDs := func(tags []string) int {
+		s := "id"
+		c := 0
+		for _, tag := range tags {
+			if tag == s {
+				c++
+			}
+		}
+		return c
+	}
+
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), ";")
+		numOfIDs += countIDs(tags)
+		fieldName := field.Name
+		if function, ok := getTagValue(tags, fn); ok && !sqlFunctionRegexp.MatchString(function) {
+			return fmt.Errorf("paginate: invalid function %q for field %q", function, fieldName)
+		}
+		if column, ok := getTagValue(tags, col); ok && !columnNameRegexp.MatchString(column) {
+			return fmt.Errorf("paginate: invalid column name %q for field %q", column, fieldName)
+		}
+		T := reflect.Indirect(p.rv).FieldByName(fieldName).Interface()
+		switch T.(type) {
+		case string:
+			continue
+		case int, int8, int16, int32, int64:
+			continue
+		case uint, uint8, uint16, uint32, uint64:
+			continue
+		case bool:
+			continue
+		case float32, float64:
+			continue
+		case time.Time:
+			continue
+		case []byte:
+			continue
+		case NullInt, NullInt64, NullInt32, NullBool, NullString, NullTime, NullFloat64, NullFloat32, NullDecimal:
+			continue
+		case *string, *int, *int64, *float64, *bool, *time.Time:
+			continue
+		default:
+			return fmt.Errorf("paginate: invalid type for field %q", fieldName)
+		}
+	}
+
+	if numOfIDs == 0 {
+		return fmt.Errorf("paginate: id has not been defined in any " +
+			"of the field of the given struct")
+	}
+
+	return nil
+}
+
+// tableMetadata holds the information that Paginator derives with reflection from
+// the fields of a table struct type. It does not depend on the request, so it is
+// computed only once for every type. See getTableMetadata.
+type tableMetadata struct {
+	// err is the error returned by validateFields for the type, if any. The
+	// rest of the fields are only set when err is nil.
+	err error
+
+	id              string
+	ids             []string
+	cols            []string
+	fields          []string
+	filters         []string
+	mappers         mappers
+	functions       map[string]string
+	jsonColumns     []string
+	arrayColumns    []string
+	noSelectColumns []string
+}
+
+// tableMetadataCache holds the *tableMetadata of every table struct type given
+// to NewPaginator, keyed by the reflect.Type of the table.
+var tableMetadataCache sync.Map
+
+// getTableMetadata returns the tableMetadata of the given table struct type,
+// computing it only the first time the type is given.
+func getTableMetadata(t reflect.Type) *tableMetadata {
+	if m, ok := tableMetadataCache.Load(t); ok {
+		return m.(*tableMetadata)
+	}
+
+	actual, _ := tableMetadataCache.LoadOrStore(t, newTableMetadata(t, nil))
+	return actual.(*tableMetadata)
+}
+
+// newTableMetadata computes the tableMetadata of the given table struct type. The
+// column names of the fields without the tag "col" will be inferred with the given
+// namer, or with parseCamelCaseToSnakeLowerCase if namer is nil.
+func newTableMetadata(t reflect.Type, namer func(fieldName string) string) *tableMetadata {
+	scratch := &paginator{rv: reflect.New(t).Elem(), columnNamer: namer}
+	m := &tableMetadata{err: scratch.validateFields()}
+	if m.err == nil {
+		scratch.getID()
+		scratch.getColsAndMapParameters()
+		scratch.getFieldNames()
+		scratch.getFilters()
+		scratch.getFunctions()
+		scratch.getJSONColumns()
+		scratch.getArrayColumns()
+		scratch.getNoSelectColumns()
+		m.id = scratch.id
+		m.ids = scratch.ids
+		m.cols = scratch.cols
+		m.fields = scratch.fields
+		m.filters = scratch.filters
+		m.mappers = scratch.mappers
+		m.functions = scratch.functions
+		m.jsonColumns = scratch.jsonColumns
+		m.arrayColumns = scratch.arrayColumns
+		m.noSelectColumns = scratch.noSelectColumns
+	}
+	return m
+}
+
+// loadTableMetadata sets the ids, columns, fields, filters, mappers, functions, json
+// columns, array columns and non-selected columns of the given table from the cached
+// tableMetadata of its type. The slices are capped, so appending to them never
+// modifies the cached ones. Call it only after validateTable succeeds.
+func (p *paginator) loadTableMetadata() {
+	m := getTableMetadata(p.rv.Type())
+	// The metadata of the column names inferred with a custom
+	// namer cannot be cached since it depends on the namer.
+	if p.columnNamer != nil {
+		m = newTableMetadata(p.rv.Type(), p.columnNamer)
+	}
+	p.id = m.id
+	p.ids = m.ids[:len(m.ids):len(m.ids)]
+	p.cols = m.cols[:len(m.cols):len(m.cols)]
+	p.fields = m.fields[:len(m.fields):len(m.fields)]
+	p.filters = m.filters[:len(m.filters):len(m.filters)]
+	p.mappers = m.mappers[:len(m.mappers):len(m.mappers)]
+	p.functions = m.functions
+	p.jsonColumns = m.jsonColumns[:len(m.jsonColumns):len(m.jsonColumns)]
+	p.arrayColumns = m.arrayColumns[:len(m.arrayColumns):len(m.arrayColumns)]
+	p.noSelectColumns = m.noSelectColumns[:len(m.noSelectColumns):len(m.noSelectColumns)]
+}
+
+// getColsAndMapParameters does two things:
+//
+// (1) It infers the column names of the database table from the given ``table``
+//     struct fields. If the fields have the tag "col", the column name will be taken
+//     from there.
+// (2) It will map the column names with request ``parameter`` names if the struct
+//     fields have the tag "param" on it. Columns of joined tables given with the
+//     tag "col" (e.g. "col=developer.programming_language") will be mapped by default
+//     with a request parameter without the table name (e.g. "programming_language").
+//
+// Malformed "col" and "param" tags will be ignored silently.
+func (p *paginator) getColsAndMapParameters() {
+
+	getParamFromTags := func(tags []string) (hasParamTag bool, paramName string) {
+		for _, tag := range tags {
+			kv := strings.Split(tag, "=")
+			if len(kv) != 2 {
+				continue
+			}
+			k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+			if k != param {
+				continue
+			}
+			return true, v
+		}
+		return hasParamTag, paramName
+	}
+
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+		name := p.getColName(field)
+		if hasParamTag, paramName := getParamFromTags(tags); hasParamTag {
+			p.mappers.Add(name, paramName)
+		} else if j := strings.LastIndex(name, "."); j != -1 {
+			p.mappers.Add(name, name[j+1:])
+		}
+		p.cols = append(p.cols, name)
+	}
+}
+
+// getColName returns the name of the database column of the given struct
+// field. If the field has the tag "col", the column name will be taken from
+// there. Otherwise, the column name will be inferred from the field name with
+// p.columnNamer, or with parseCamelCaseToSnakeLowerCase by default.
+func (p *paginator) getColName(field reflect.StructField) string {
+	tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+	for _, tag := range tags {
+		kv := strings.Split(tag, "=")
+		if len(kv) != 2 {
+			continue
+		}
+		k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if k != col {
+			continue
+		}
+		return v
+	}
+	if p.columnNamer != nil {
+		return p.columnNamer(field.Name)
+	}
+	return parseCamelCaseToSnakeLowerCase(field.Name)
+}
+
+// validateColumnNames returns an error if any of the column names inferred
+// with p.columnNamer is not a valid column name. See the ColumnNamer option.
+func (p *paginator) validateColumnNames() error {
+	if p.columnNamer == nil {
+		return nil
+	}
+	for i, c := range p.cols {
+		if !columnNameRegexp.MatchString(c) {
+			return fmt.Errorf("paginate: invalid column name %q for field %q", c, p.fields[i])
+		}
+	}
+	return nil
+}
+
+// validateTags returns an error if any of the fields of the given table has an
+// unknown or malformed tag, or an invalid "param" name, or if two fields have the
+// same column or request parameter. See ValidateTable. Call it only after
+// loadTableMetadata.
+func (p *paginator) validateTags() error {
+	flags := []string{"id", filter, _json, _array, noselect}
+	keys := []string{col, param, fn}
+
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tag := field.Tag.Get("paginate")
+		if tag == "" {
+			continue
+		}
+		for _, t := range strings.Split(tag, tagsep) {
+			if isStringIn(t, flags) {
+				continue
+			}
+			kv := strings.Split(t, "=")
+			if len(kv) != 2 || !isStringIn(strings.TrimSpace(kv[0]), keys) {
+				return fmt.Errorf("paginate: unknown tag %q for field %q", t, field.Name)
+			}
+			k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+			if k == param && !paramNameRegexp.MatchString(v) {
+				return fmt.Errorf("paginate: invalid parameter name %q for field %q", v, field.Name)
+			}
+		}
+	}
+
+	params := make([]string, 0, len(p.filters))
+	for i, c := range p.cols {
+		if j := indexOf(c, p.cols); j != i {
+			return fmt.Errorf("paginate: fields %q and %q have the same column %q", p.fields[j], p.fields[i], c)
+		}
+		if !isStringIn(c, p.filters) {
+			continue
+		}
+		name := c
+		if isMapped, paramName := p.mappers.isColumnMapped(c); isMapped {
+			name = paramName
+		}
+		if isStringIn(name, params) {
+			return fmt.Errorf("paginate: more than one field is filtered with the request parameter %q", name)
+		}
+		params = append(params, name)
+	}
+	return nil
+}
+
+func (p *paginator) getFieldNames() {
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		fieldName := field.Name
+		p.fields = append(p.fields, fieldName)
+	}
+}
+
+// excludeColumns removes the fields given with the ExcludeColumns option from
+// p.cols and p.fields, so they will not be selected, filtered or sorted.
+func (p *paginator) excludeColumns() error {
+	if len(p.excluded) == 0 {
+		return nil
+	}
+
+	for _, name := range p.excluded {
+		if !isStringIn(name, p.fields) && !isStringIn(name, p.cols) {
+			return fmt.Errorf("paginate: cannot exclude %q since it is not a field or column of table %s", name, p.name)
+		}
+		for _, id := range p.ids {
+			if name == id || name == p.fields[indexOf(id, p.cols)] {
+				return fmt.Errorf("paginate: cannot exclude the id %q of table %s", id, p.name)
+			}
+		}
+	}
+
+	cols := make([]string, 0, len(p.cols))
+	fields := make([]string, 0, len(p.fields))
+	for i, c := range p.cols {
+		if isStringIn(c, p.excluded) || isStringIn(p.fields[i], p.excluded) {
+			continue
+		}
+		cols = append(cols, c)
+		fields = append(fields, p.fields[i])
+	}
+	p.cols = cols
+	p.fields = fields
+	return nil
+}
+
+// getSelectedColumns sets p.selectedCols and p.selectedFields with the columns
+// and fields given with the Columns option, or with all the columns and fields
+// of the table if the option was not used, narrowed down by the fields given in
+// the request parameter p.fieldsParam. The columns of the fields with the tag
+// "noselect" will never be selected. The id will always be selected.
+func (p *paginator) getSelectedColumns() error {
+	for i, names := range [][]string{p.projection, p.requestFields, p.requestExcluded} {
+		// The unknown fields given in the request will not match any column.
+		if i > 0 && p.ignoreUnknownFields {
+			continue
+		}
+		for _, name := range names {
+			if !isStringIn(name, p.fields) && !isStringIn(name, p.cols) {
+				return fmt.Errorf("paginate: cannot select %q since it is not a field or column of table %s", name, p.name)
+			}
+		}
+	}
+
+	// isIn reports whether the column or the field of the given index is in names.
+	isIn := func(i int, names []string) bool {
+		return isStringIn(p.cols[i], names) || isStringIn(p.fields[i], names)
+	}
+
+	p.selectedCols = make([]string, 0, len(p.cols))
+	p.selectedFields = make([]string, 0, len(p.fields))
+	for i, c := range p.cols {
+		if !isStringIn(c, p.ids) {
+			if isStringIn(c, p.noSelectColumns) {
+				continue
+			}
+			if len(p.projection) > 0 && !isIn(i, p.projection) {
+				continue
+			}
+			if len(p.requestFields) > 0 && !isIn(i, p.requestFields) {
+				continue
+			}
+			if isIn(i, p.requestExcluded) {
+				continue
+			}
+		}
+		p.selectedCols = append(p.selectedCols, c)
+		p.selectedFields = append(p.selectedFields, p.fields[i])
+	}
+	return nil
+}
+
+func (p *paginator) getFilters() {
+
+	hasfilter := func(tags []string) bool {
+		for _, tag := range tags {
+			if tag == filter {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+		if !hasfilter(tags) {
+			continue
+		}
+		p.filters = append(p.filters, p.getColName(field))
+	}
+}
+
+// getFunctions maps the column names of the fields with the tag "fn"
+// (e.g. `paginate:"filter;fn=DATE"`) with the given sql function.
+func (p *paginator) getFunctions() {
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+		if function, ok := getTagValue(tags, fn); ok {
+			if p.functions == nil {
+				p.functions = make(map[string]string)
+			}
+			p.functions[p.getColName(field)] = function
+		}
+	}
+}
+
+// getJSONColumns gets the column names of the filter fields with the tag
+// "json" (e.g. `paginate:"filter;json"`). See getJSONParameters.
+func (p *paginator) getJSONColumns() {
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+		if isStringIn(filter, tags) && isStringIn(_json, tags) {
+			p.jsonColumns = append(p.jsonColumns, p.getColName(field))
+		}
+	}
+}
+
+// getArrayColumns gets the column names of the filter fields with the tag
+// "array" (e.g. `paginate:"filter;array"`). See setArraySigns.
+func (p *paginator) getArrayColumns() {
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+		if isStringIn(filter, tags) && isStringIn(_array, tags) {
+			p.arrayColumns = append(p.arrayColumns, p.getColName(field))
+		}
+	}
+}
+
+// getNoSelectColumns gets the column names of the fields with the tag "noselect"
+// (e.g. `paginate:"filter;noselect"`). See getSelectedColumns.
+func (p *paginator) getNoSelectColumns() {
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+		if isStringIn(noselect, tags) {
+			p.noSelectColumns = append(p.noSelectColumns, p.getColName(field))
+		}
+	}
+}
+
+// getID sets p.ids with the columns of the fields tagged with "id" in
+// declaration order and p.id with the first one.
+func (p *paginator) getID() {
+	ids := make([]string, 0, 1)
+
+	hasID := func(tags []string) bool {
+		for _, tag := range tags {
+			if tag == "id" {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < p.rv.NumField(); i++ {
+		field := p.rv.Type().Field(i)
+		tags := strings.Split(field.Tag.Get("paginate"), tagsep)
+		if hasID(tags) {
+			ids = append(ids, p.getColName(field))
+		}
+	}
+
+	p.ids = ids
+	if len(ids) > 0 {
+		p.id = ids[0]
+	}
+}
+
+func (p *paginator) getTableName() {
+	name := parseCamelCaseToSnakeLowerCase(p.rv.Type().Name())
+	p.name = name
+}
+
+func (p *paginator) GetRowPtrArgs() []interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started {
+		return nil
+	}
+	if len(p.tmp) > 0 {
+		p.addRow()
+	}
+	for _, fieldName := range p.selectedFields {
+		I := reflect.Indirect(p.rv).FieldByName(fieldName).Interface()
+		switch I.(type) {
+		case NullInt:
 			var ni NullInt
 			p.tmp = append(p.tmp, &ni)
+		case NullInt64:
+			var ni64 NullInt64
+			p.tmp = append(p.tmp, &ni64)
+		case NullInt32:
+			var ni32 NullInt32
+			p.tmp = append(p.tmp, &ni32)
 		case NullBool:
 			var nb NullBool
 			p.tmp = append(p.tmp, &nb)
@@ -484,22 +1864,27 @@ func (p *paginator) GetRowPtrArgs() []interface{} {
 		case NullFloat64:
 			var n NullFloat64
 			p.tmp = append(p.tmp, &n)
+		case NullFloat32:
+			var n NullFloat32
+			p.tmp = append(p.tmp, &n)
+		case NullDecimal:
+			var n NullDecimal
+			p.tmp = append(p.tmp, &n)
 		case string:
 			var s sql.NullString
 			p.tmp = append(p.tmp, &s)
 		case int:
 			var i int
 			p.tmp = append(p.tmp, &i)
-		case int8:
-			var i8 int8
-			p.tmp = append(p.tmp, &i8)
-		case int16:
-			var i16 int16
-			i16 = 0
-			p.tmp = append(p.tmp, &i16)
+		case int8, int16:
+			i := nullInt{bitSize: reflect.TypeOf(I).Bits()}
+			p.tmp = append(p.tmp, &i)
 		case int32:
 			var i32 sql.NullInt32
 			p.tmp = append(p.tmp, &i32)
+		case uint, uint8, uint16, uint32, uint64:
+			u := nullUint{bitSize: reflect.TypeOf(I).Bits()}
+			p.tmp = append(p.tmp, &u)
 		case int64:
 			var i64 sql.NullInt64
 			p.tmp = append(p.tmp, &i64)
@@ -515,17 +1900,57 @@ func (p *paginator) GetRowPtrArgs() []interface{} {
 		case time.Time:
 			var t sql.NullTime
 			p.tmp = append(p.tmp, &t)
+		case []byte:
+			// We cannot use sql.RawBytes since its memory is owned by the
+			// sql driver and reused by the next call to sql.Rows.Next, so
+			// the scanned bytes are copied in b. NULL values will give nil.
+			var b []byte
+			p.tmp = append(p.tmp, &b)
+		case *string:
+			var s sql.NullString
+			p.tmp = append(p.tmp, &s)
+		case *int, *int64:
+			var i64 sql.NullInt64
+			p.tmp = append(p.tmp, &i64)
+		case *float64:
+			var f64 sql.NullFloat64
+			p.tmp = append(p.tmp, &f64)
+		case *bool:
+			var b sql.NullBool
+			p.tmp = append(p.tmp, &b)
+		case *time.Time:
+			var t sql.NullTime
+			p.tmp = append(p.tmp, &t)
 		}
 	}
 
 	// As an special case in tmp we will always
 	// append at the end p.totalSize whose value
 	// is going to be set when the query gets executed.
-	p.tmp = append(p.tmp, &p.totalSize)
+	// This is not needed when the total size is fetched
+	// with a separate count query.
+	p.countColumn = -1
+	if !p.separateCount {
+		p.countColumn = len(p.tmp)
+		p.tmp = append(p.tmp, &p.totalSize)
+	}
 
 	return p.tmp
 }
 
+// fieldValues returns the values of p.tmp that will be set in the fields of
+// the given table, that is, all the values except the one of the count column.
+func (p *paginator) fieldValues() []interface{} {
+	values := make([]interface{}, 0, len(p.tmp))
+	for i, value := range p.tmp {
+		if i == p.countColumn {
+			continue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
 // addRow adds a new row in p.rows.
 //
 // The elements of p.rows will be instances of the given table struct.
@@ -543,8 +1968,10 @@ func (p *paginator) GetRowPtrArgs() []interface{} {
 // 		- sql.NullBool
 // 		- sql.NullTime
 //
-// Custom nullable values will not be handled, for example, for
-// values like uint8, uint16, etc.
+// Fields of the uint family will be handled with nullUint, and the
+// int8 and int16 fields with nullInt, which check that the scanned
+// values fit in the fields. Fields of type []byte will be left nil
+// when the scanned value is NULL.
 //
 // It is up to GetRowPtrArgs to call addRow each time a new row is
 // read by sql.Rows.Scan. NextData is also responsible to call addRow
@@ -554,76 +1981,478 @@ func (p *paginator) GetRowPtrArgs() []interface{} {
 // Finally, Scan will also call addRow only once in case there values left in
 // p.tmp.
 func (p *paginator) addRow() {
+	// The values of p.tmp without the count column are in
+	// the same order as the fields of the given table.
+	values := p.fieldValues()
+	row := p.newRow(values)
+
+	rawRow := make([]interface{}, 0, len(p.selectedFields))
+	for _, value := range values {
+		rawRow = append(rawRow, normalizeNullable(reflect.Indirect(reflect.ValueOf(value)).Interface()))
+	}
+	p.rawRows = append(p.rawRows, rawRow)
+
+	// We need to clear p.tmp so we can reuse it later for another call
+	// to addRow.
+	p.tmp = make([]interface{}, 0)
+
+	p.rows = append(p.rows, row)
+}
+
+// newRow returns a new instance of the given table struct with its fields set
+// with the given scanned values. See addRow.
+func (p *paginator) newRow(values []interface{}) interface{} {
 	row := p.table
 	rowrv := reflect.ValueOf(&row).Elem()
 	tmpRow := reflect.New(rowrv.Elem().Type()).Elem()
 	tmpRow.Set(rowrv.Elem())
 
-	// The below loop condition expression is len(tmp)-1
-	// because of the extra field we are adding in p.tmp: totalSize.
-	// len(tmp)-1  will give us exactly the field elements we want
-	// from the given table.
-	for i := 0; i < len(p.tmp)-1; i++ {
-		I := reflect.Indirect(reflect.ValueOf(p.tmp[i])).Interface()
-		tmpRowField := tmpRow.FieldByName(p.fields[i])
+	for i := 0; i < len(p.selectedFields); i++ {
+		I := reflect.Indirect(reflect.ValueOf(values[i])).Interface()
+		tmpRowField := tmpRow.FieldByName(p.selectedFields[i])
+
+		// Pointer fields will be left nil when the scanned value is NULL.
+		if tmpRowField.Kind() == reflect.Ptr {
+			setPointerField(tmpRowField, I)
+			rowrv.Set(tmpRow)
+			continue
+		}
 
 		switch I.(type) {
 		case sql.NullString:
 			ns := sql.NullString{}
 			nsrv := reflect.ValueOf(&ns).Elem()
-			nsrv.Set(reflect.ValueOf(p.tmp[i]).Elem())
+			nsrv.Set(reflect.ValueOf(values[i]).Elem())
 			tmpRowField.SetString(ns.String)
 		case sql.NullInt32:
 			ni32 := sql.NullInt32{}
 			ni32rv := reflect.ValueOf(&ni32).Elem()
-			ni32rv.Set(reflect.ValueOf(p.tmp[i]).Elem())
-			tmpRowField.Set(reflect.ValueOf(ni32.Int32))
+			ni32rv.Set(reflect.ValueOf(values[i]).Elem())
+			tmpRowField.SetInt(int64(ni32.Int32))
 		case sql.NullInt64:
 			ni64 := sql.NullInt64{}
 			ni64rv := reflect.ValueOf(&ni64).Elem()
-			ni64rv.Set(reflect.ValueOf(p.tmp[i]).Elem())
+			ni64rv.Set(reflect.ValueOf(values[i]).Elem())
 			tmpRowField.Set(reflect.ValueOf(ni64.Int64))
 		case sql.NullFloat64:
 			nf64 := sql.NullFloat64{}
 			nf64rv := reflect.ValueOf(&nf64).Elem()
-			nf64rv.Set(reflect.ValueOf(p.tmp[i]).Elem())
+			nf64rv.Set(reflect.ValueOf(values[i]).Elem())
 			tmpRowField.Set(reflect.ValueOf(nf64.Float64))
 		case sql.NullBool:
 			nb := sql.NullBool{}
 			nbrv := reflect.ValueOf(&nb).Elem()
-			nbrv.Set(reflect.ValueOf(p.tmp[i]).Elem())
+			nbrv.Set(reflect.ValueOf(values[i]).Elem())
 			tmpRowField.Set(reflect.ValueOf(nb.Bool))
+		case nullUint:
+			tmpRowField.SetUint(I.(nullUint).Uint)
+		case nullInt:
+			tmpRowField.SetInt(I.(nullInt).Int)
+		case []byte:
+			tmpRowField.SetBytes(I.([]byte))
 		case sql.NullTime:
 			nt := sql.NullTime{}
 			ntrv := reflect.ValueOf(&nt).Elem()
-			ntrv.Set(reflect.ValueOf(p.tmp[i]).Elem())
+			ntrv.Set(reflect.ValueOf(values[i]).Elem())
 			tmpRowField.Set(reflect.ValueOf(nt.Time))
 		default:
-			val := reflect.ValueOf(p.tmp[i]).Elem()
-			tmpRow.FieldByName(p.fields[i]).Set(val)
+			val := reflect.ValueOf(values[i]).Elem()
+			tmpRow.FieldByName(p.selectedFields[i]).Set(val)
 		}
 
 		rowrv.Set(tmpRow)
 	}
 
-	// We need to clear p.tmp so we can reuse it later for another call
-	// to addRow.
-	p.tmp = make([]interface{}, 0)
+	if p.location != nil {
+		p.convertTimes(tmpRow)
+		rowrv.Set(tmpRow)
+	}
+
+	p.trackLastModified(rowrv.Elem())
+	p.lastRow = row
+
+	return row
+}
+
+// normalizeNullable returns nil if the given nullable value from the sql package
+// (e.g. sql.NullString) is NULL or its underlying value otherwise. Other values
+// are returned as they are.
+func normalizeNullable(v interface{}) interface{} {
+	switch n := v.(type) {
+	case sql.NullString:
+		if n.Valid {
+			return n.String
+		}
+	case sql.NullInt32:
+		if n.Valid {
+			return n.Int32
+		}
+	case sql.NullInt64:
+		if n.Valid {
+			return n.Int64
+		}
+	case sql.NullFloat64:
+		if n.Valid {
+			return n.Float64
+		}
+	case sql.NullBool:
+		if n.Valid {
+			return n.Bool
+		}
+	case sql.NullTime:
+		if n.Valid {
+			return n.Time
+		}
+	case nullUint:
+		if n.Valid {
+			return n.Uint
+		}
+	case nullInt:
+		if n.Valid {
+			return n.Int
+		}
+	case driver.Valuer:
+		value, err := n.Value()
+		if err == nil {
+			return value
+		}
+		return v
+	default:
+		return v
+	}
+	return nil
+}
+
+// setPointerField sets the given pointer field with the value of the given
+// nullable value from the sql package (e.g. sql.NullString). If the nullable
+// value is NULL the field will be set to nil.
+func setPointerField(field reflect.Value, nullable interface{}) {
+	valuer, ok := nullable.(driver.Valuer)
+	if !ok {
+		return
+	}
+	v, err := valuer.Value()
+	if err != nil || v == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+	ptr := reflect.New(field.Type().Elem())
+	ptr.Elem().Set(reflect.ValueOf(v).Convert(field.Type().Elem()))
+	field.Set(ptr)
+}
+
+// clampPageNumber reduces p.pageNumber to the last page whose offset is within
+// p.maxOffset when the MaxOffset option is used. An offset given directly in the
+// request is reduced to p.maxOffset. Under strict paging the page number or the
+// offset are not reduced and ErrOffsetTooDeep is returned instead.
+func (p *paginator) clampPageNumber() error {
+	p.offsetClamped = false
+	if !p.hasMaxOffset || p.currentOffset() <= p.maxOffset {
+		return nil
+	}
+	if p.strictPaging {
+		return ErrOffsetTooDeep
+	}
+	if p.hasOffset {
+		p.offset = p.maxOffset
+		p.pageNumber = p.offset/p.pageSize + 1
+	} else {
+		p.pageNumber = p.maxOffset/p.pageSize + 1
+	}
+	p.offsetClamped = true
+	return nil
+}
+
+// currentOffset returns the number of records to skip to reach the current
+// page, i.e. p.offset if it was given directly in the request.
+func (p *paginator) currentOffset() int {
+	if p.hasOffset {
+		return p.offset
+	}
+	return getOffset(p.pageNumber, p.pageSize)
+}
+
+// applyLimitOffset sets the page size of the given request data and p.offset with
+// the values of the ``limit`` and ``offset`` request parameters, which take
+// precedence over the page and page size parameters. Invalid values are ignored,
+// unless p.strictPaging is true, in which case an error is returned, as well as
+// when the limit or the offset are given together with the page size or the page.
+func (p *paginator) applyLimitOffset(v url.Values, request *paginationRequest) error {
+	if limit := v.Get(defaultLimitParam); limit != "" {
+		n, err := strconv.Atoi(limit)
+		switch {
+		case (err != nil || n <= 0) && p.strictPaging:
+			return fmt.Errorf("paginate: %s should be a number greater than zero; got %q", defaultLimitParam, limit)
+		case p.strictPaging && v.Get(p.pageSizeParam) != "":
+			return fmt.Errorf("paginate: %s and %s cannot be given together", defaultLimitParam, p.pageSizeParam)
+		case err == nil && n > 0:
+			request.pageSize = n
+		}
+	}
+
+	if offset := v.Get(defaultOffsetParam); offset != "" {
+		n, err := strconv.Atoi(offset)
+		switch {
+		case (err != nil || n < 0) && p.strictPaging:
+			return fmt.Errorf("paginate: %s should be a number greater than or equal to zero; got %q", defaultOffsetParam, offset)
+		case p.strictPaging && v.Get(p.pageParam) != "":
+			return fmt.Errorf("paginate: %s and %s cannot be given together", defaultOffsetParam, p.pageParam)
+		case err == nil && n >= 0:
+			p.offset = n
+			p.hasOffset = true
+		}
+	}
+	return nil
+}
+
+// convertTimes converts the time fields of the given row to p.location.
+func (p *paginator) convertTimes(row reflect.Value) {
+	for _, fieldName := range p.selectedFields {
+		field := row.FieldByName(fieldName)
+		switch v := field.Interface().(type) {
+		case time.Time:
+			field.Set(reflect.ValueOf(v.In(p.location)))
+		case NullTime:
+			if v.Valid {
+				v.Time = v.Time.In(p.location)
+				field.Set(reflect.ValueOf(v))
+			}
+		case *time.Time:
+			if v != nil {
+				t := v.In(p.location)
+				field.Set(reflect.ValueOf(&t))
+			}
+		}
+	}
+}
+
+// converters returns the converters of the values given in the request to filter
+// the time columns when the Location or TimeLayout options are used, and the numeric
+// columns. The values of the time columns will be converted to time.Time in p.location,
+// while the values of the IN and NOT IN clauses of the numeric columns will be converted
+// to int64, uint64 or float64 depending on the type of the field of the column. Values
+// that cannot be converted are left as they are. Columns filtered through an sql function,
+// see the "fn" tag, are left out since the function might expect other values.
+func (p *paginator) converters() map[string]func(sign, value string) interface{} {
+	converters := make(map[string]func(sign, value string) interface{})
+	for i, c := range p.cols {
+		if _, ok := p.functions[c]; ok {
+			continue
+		}
+		switch p.rv.FieldByName(p.fields[i]).Interface().(type) {
+		case time.Time, *time.Time, NullTime:
+			if p.location == nil && p.timeLayout == "" {
+				continue
+			}
+			converters[c] = func(sign, v string) interface{} {
+				if t, ok := p.parseTime(v); ok {
+					return t
+				}
+				return v
+			}
+		case int, int8, int16, int32, int64, *int, *int64, NullInt, NullInt64, NullInt32:
+			converters[c] = func(sign, v string) interface{} {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && (sign == _in || sign == _notin) {
+					return n
+				}
+				return v
+			}
+		case uint, uint8, uint16, uint32, uint64:
+			converters[c] = func(sign, v string) interface{} {
+				if n, err := strconv.ParseUint(v, 10, 64); err == nil && (sign == _in || sign == _notin) {
+					return n
+				}
+				return v
+			}
+		case float32, float64, *float64, NullFloat64, NullFloat32:
+			converters[c] = func(sign, v string) interface{} {
+				if f, err := strconv.ParseFloat(v, 64); err == nil && (sign == _in || sign == _notin) {
+					return f
+				}
+				return v
+			}
+		case bool, *bool, NullBool:
+			converters[c] = func(sign, v string) interface{} {
+				if b, ok := parseBool(v); ok {
+					return b
+				}
+				return v
+			}
+		}
+	}
+	return converters
+}
+
+// parseTime parses the given timestamp of a time column given in the request with
+// p.timeLayout, or with any of the timeLayouts if the TimeLayout option was not used.
+// Timestamps without a time zone will be interpreted in p.location, or in UTC if the
+// Location option was not used.
+func (p *paginator) parseTime(value string) (time.Time, bool) {
+	loc := p.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if p.timeLayout == "" {
+		return parseTimeInLocation(value, loc)
+	}
+	t, err := time.ParseInLocation(p.timeLayout, value, loc)
+	return t, err == nil
+}
+
+// validateFilters returns an error if any of the values given in the request
+// to filter the columns cannot be converted to the type of the column.
+func (p *paginator) validateFilters() error {
+	if err := p.validateTimeFilters(); err != nil {
+		return err
+	}
+	return p.validateBoolFilters()
+}
+
+// validateBoolFilters returns an error if any of the values given in the request
+// to filter the boolean columns is not a boolean, i.e. true, false, 1 or 0, so
+// they are never given to the database as raw strings.
+func (p *paginator) validateBoolFilters() error {
+	boolColumns := make([]string, 0)
+	for i, c := range p.cols {
+		if _, ok := p.functions[c]; ok {
+			continue
+		}
+		switch p.rv.FieldByName(p.fields[i]).Interface().(type) {
+		case bool, *bool, NullBool:
+			boolColumns = append(boolColumns, c)
+		}
+	}
+
+	for _, param := range p.parameters {
+		if !isStringIn(param.name, boolColumns) {
+			continue
+		}
+		for _, v := range filterValues(param) {
+			if _, ok := parseBool(v); !ok {
+				return fmt.Errorf("paginate: invalid boolean %q for column %q; should be true, false, 1 or 0", v, param.name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTimeFilters returns an error if any of the values given in the request
+// to filter the time columns does not have the layout given with the TimeLayout
+// option, so they are never given to the database as raw strings.
+func (p *paginator) validateTimeFilters() error {
+	if p.timeLayout == "" {
+		return nil
+	}
+
+	timeColumns := make([]string, 0)
+	for i, c := range p.cols {
+		if _, ok := p.functions[c]; ok {
+			continue
+		}
+		switch p.rv.FieldByName(p.fields[i]).Interface().(type) {
+		case time.Time, *time.Time, NullTime:
+			timeColumns = append(timeColumns, c)
+		}
+	}
+
+	for _, param := range p.parameters {
+		if !isStringIn(param.name, timeColumns) {
+			continue
+		}
+		for _, v := range filterValues(param) {
+			if _, ok := p.parseTime(v); !ok {
+				return fmt.Errorf("paginate: invalid time %q for column %q; should have the layout %q", v, param.name, p.timeLayout)
+			}
+		}
+	}
+	return nil
+}
+
+// trackLastModified updates p.lastModified with the value of the
+// p.lastModifiedColumn in the given row if it is more recent.
+func (p *paginator) trackLastModified(row reflect.Value) {
+	for i, c := range p.selectedCols {
+		if c != p.lastModifiedColumn {
+			continue
+		}
+
+		var t time.Time
+		switch v := row.FieldByName(p.selectedFields[i]).Interface().(type) {
+		case time.Time:
+			t = v
+		case NullTime:
+			if !v.Valid {
+				return
+			}
+			t = v.Time
+		case *time.Time:
+			if v == nil {
+				return
+			}
+			t = *v
+		default:
+			return
+		}
+
+		if t.IsZero() {
+			return
+		}
+
+		if !p.hasLastModified || t.After(p.lastModified) {
+			p.lastModified = t
+			p.hasLastModified = true
+		}
+		return
+	}
+}
+
+func (p *paginator) LastModified() (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	p.rows = append(p.rows, row)
+	// There might be values left in p.tmp that have not been
+	// added to p.rows yet. See addRow for more.
+	if len(p.tmp) > 0 {
+		p.addRow()
+	}
+	return p.lastModified, p.hasLastModified
 }
 
 func (p *paginator) NextData() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if len(p.tmp) > 0 {
 		p.addRow()
 	}
+	p.scanned = true
 	if p.stop || p.closed {
 		return false
 	}
 	return len(p.rows) > 0
 }
 
+// finalize adds the pending row of the last call to GetRowPtrArgs, if any, and sets
+// p.pageCount with the number of rows of the page. It only takes effect the first
+// time it is called after the rows of the page are fetched, so the count does not
+// change while the rows are consumed by Scan.
+func (p *paginator) finalize() {
+	p.once.Do(func() {
+		// Order matters. If there is some data left in p.tmp,
+		// p.addRow will add a new row with the p.tmp data affecting,
+		// therefore, the value of p.pageCount.
+		if len(p.tmp) > 0 {
+			p.addRow()
+		}
+		p.started = true
+		p.pageCount = len(p.rows)
+	})
+}
+
 func (p *paginator) Scan(dest interface{}) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	defer func() {
 		if err != nil {
 			p.stop = true
@@ -646,34 +2475,143 @@ func (p *paginator) Scan(dest interface{}) (err error) {
 		return errors.New("paginate: Scan called without calling NextData")
 	}
 
-	p.once.Do(func() {
-		// Order matters. If there is some data left in p.tmp,
-		// p.addRow will add a new row with the p.tmp data affecting,
-		// therefore, the value of p.pageCount.
-		if len(p.tmp) > 0 {
-			p.addRow()
-		}
-		p.started = true
-		p.pageCount = len(p.rows)
-	})
+	p.finalize()
 
 	destrv := reflect.ValueOf(dest)
 
 	row := p.rows[0]
-	for _, field := range p.fields {
+	for _, field := range p.selectedFields {
 		val := reflect.ValueOf(row).FieldByName(field)
 		destrv.Elem().FieldByName(field).Set(val)
 	}
 
-	// Let's remove the row from p.rows.
+	p.removeRow()
+
+	return nil
+}
+
+func (p *paginator) ScanMap() (m map[string]interface{}, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			p.stop = true
+		}
+	}()
+
+	if p.closed {
+		return nil, ErrPaginatorIsClosed
+	}
+
+	if len(p.rows) == 0 {
+		return nil, errors.New("paginate: ScanMap called without calling NextData")
+	}
+
+	p.finalize()
+
+	m = make(map[string]interface{}, len(p.selectedCols))
+	for i, value := range p.rawRows[0] {
+		m[p.selectedCols[i]] = value
+	}
+
+	p.removeRow()
+
+	return m, nil
+}
+
+// removeRow removes the first row of p.rows after it has been scanned.
+func (p *paginator) removeRow() {
 	p.rows = p.rows[1:]
+	p.rawRows = p.rawRows[1:]
+	p.consumed++
 
 	// When all rows are consumed, we "close" the Paginator Scanner.
 	if len(p.rows) == 0 {
 		p.closed = true
 	}
+}
 
-	return nil
+func (p *paginator) ScanRows() ([][]interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrPaginatorIsClosed
+	}
+
+	p.finalize()
+	p.scanned = true
+
+	rows := p.rawRows
+	p.rows = nil
+	p.rawRows = nil
+	p.closed = true
+	p.consumed += len(rows)
+
+	return rows, nil
+}
+
+func (p *paginator) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The values of the last row given by GetRowPtrArgs
+	// are added to p.rows by NextData or Scan.
+	if len(p.tmp) > 0 {
+		return len(p.rows) + 1
+	}
+	return len(p.rows)
+}
+
+func (p *paginator) Consumed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.consumed
+}
+
+func (p *paginator) ScanMaps() ([]map[string]interface{}, error) {
+	rows, err := p.ScanRows()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := p.outputKeys()
+	maps := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		m := make(map[string]interface{}, len(keys))
+		for i, key := range keys {
+			if key == "" {
+				continue
+			}
+			m[key] = row[i]
+		}
+		maps = append(maps, m)
+	}
+	return maps, nil
+}
+
+// outputKeys returns the names that identify the selected columns in the
+// output of ScanMaps: the name in the ``json`` tag of their fields or the
+// column name if there is no ``json`` tag. Fields with the tag ``json:"-"``
+// get an empty name.
+func (p *paginator) outputKeys() []string {
+	keys := make([]string, 0, len(p.selectedFields))
+	for i, fieldName := range p.selectedFields {
+		field, _ := p.rv.Type().FieldByName(fieldName)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			keys = append(keys, "")
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = p.selectedCols[i]
+		}
+		keys = append(keys, name)
+	}
+	return keys
 }
 
 func (p *paginator) validateDest(dest interface{}) error {
@@ -702,13 +2640,37 @@ func (p *paginator) validateDest(dest interface{}) error {
 	return nil
 }
 
+func (p *paginator) AddHavingClause(clause RawHavingClause) error {
+	occurrences := countPlaceholders(clause.predicate, clause.dialect)
+	if occurrences == 0 && len(clause.args) > 0 {
+		return fmt.Errorf("paginate: cannot receive arguments when placeholders are not defined")
+	}
+	if occurrences > 0 && occurrences != len(clause.args) {
+		return fmt.Errorf("paginate: the number of placeholders and arguments in the having clause should be the same")
+	}
+
+	if err := dialectPlaceholder.CheckIfDialectIsSupported(clause.dialect); err != nil {
+		return fmt.Errorf("paginate: the dialect specified in the RawHavingClause is not supported")
+	}
+
+	if clause.dialect != p.dialect {
+		return fmt.Errorf("paginate: the dialect %q of the having clause does not match the dialect %q of the paginator", clause.dialect, p.dialect)
+	}
+
+	p.havingPredicates = append(p.havingPredicates, clause)
+	return nil
+}
+
 func (p *paginator) AddWhereClause(clause RawWhereClause) error {
-	re := regexp.MustCompile("[?]")
-	occurrences := re.FindAll([]byte(clause.predicate), -1)
-	if len(occurrences) == 0 && len(clause.args) > 0 {
+	if clause.err != nil {
+		return clause.err
+	}
+
+	occurrences := countPlaceholders(clause.predicate, clause.dialect)
+	if occurrences == 0 && len(clause.args) > 0 {
 		return fmt.Errorf("paginate: cannot receive arguments when placeholders are not defined")
 	}
-	if len(occurrences) > 0 && len(occurrences) != len(clause.args) {
+	if occurrences > 0 && occurrences != len(clause.args) {
 		return fmt.Errorf("paginate: the number of placeholders and arguments in the where clause should be the same")
 	}
 
@@ -716,34 +2678,484 @@ func (p *paginator) AddWhereClause(clause RawWhereClause) error {
 		return fmt.Errorf("paginate: the dialect specified in the RawWhereClause is not supported")
 	}
 
+	if p.strictRawClauses {
+		if err := validateRawIdentifiers(clause.predicate, p.name, p.cols, p.rawClauseTables); err != nil {
+			return err
+		}
+	}
+
 	p.predicates = append(p.predicates, clause)
 	return nil
 }
 
-func (p *paginator) AddJoinClause(clause interface{}) error {
-	switch v := clause.(type) {
-	case InnerJoin:
-		v.clean()
+func (p *paginator) AppliedFilters() []AppliedFilter {
+	filters := make([]AppliedFilter, 0)
+	for _, name := range p.cols {
+		for _, param := range p.parameters {
+			if param.name != name && !isJSONPathOf(param.name, name) {
+				continue
+			}
+			filters = append(filters, AppliedFilter{
+				Column:   param.name,
+				Operator: param.sign,
+				Value:    param.value,
+			})
+		}
+	}
+	return filters
+}
+
+func (p *paginator) SetFilterConjunction(conjunction string) error {
+	conjunction = strings.ToUpper(strings.TrimSpace(conjunction))
+	if conjunction != _and && conjunction != _or {
+		return fmt.Errorf("paginate: invalid filter conjunction %q; use AND or OR", conjunction)
+	}
+	p.filterConjunction = conjunction
+	return nil
+}
+
+func (p *paginator) AddJoinClause(clause JoinClause) error {
+	return p.addJoinClause(clause, false)
+}
+
+func (p *paginator) AddConditionalJoinClause(clause JoinClause) error {
+	return p.addJoinClause(clause, true)
+}
+
+// addJoinClause validates and adds the given join clause to p.joins. See
+// activeJoins for the meaning of conditional.
+func (p *paginator) addJoinClause(clause JoinClause, conditional bool) error {
+	if clause == nil {
+		return fmt.Errorf("paginate: cannot pass nil as join clause")
+	}
+
+	join := clause.joinClause()
+	join.table = p.name
+	join.conditional = conditional
+
+	if join.dialect != p.dialect {
+		return fmt.Errorf("paginate: the dialect %q of the join clause does not match the dialect %q of the paginator", join.dialect, p.dialect)
+	}
+
+	if join.raw != "" {
+		if err := validateRawJoinClause(join, conditional); err != nil {
+			return err
+		}
+	} else if join.targetTable == "" || len(join.conditions) == 0 {
+		return fmt.Errorf("paginate: join clause is empty")
+	}
 
-		if v.column == "" || v.targetTable == "" || v.targetColumn == "" {
+	for _, c := range join.conditions {
+		if c.column == "" || c.targetColumn == "" {
 			return fmt.Errorf("paginate: join clause is empty")
 		}
+		if !isStringIn(c.column, p.cols) {
+			return fmt.Errorf("paginate: given column %s in join clause does not exist in table %s", c.column, p.name)
+		}
+	}
+
+	for _, j := range p.joins {
+		if j.String() == join.String() {
+			return fmt.Errorf("paginate: given join clause %q was already given", join.String())
+		}
+	}
+
+	if len(join.selections) > 0 {
+		if conditional {
+			return fmt.Errorf("paginate: join clauses selecting columns cannot be conditional")
+		}
+		if p.distinct {
+			return fmt.Errorf("paginate: join clauses selecting columns cannot be used with the Distinct option")
+		}
+		if err := p.selectJoinColumns(join); err != nil {
+			return err
+		}
+	}
+
+	p.joins = append(p.joins, join)
+
+	return nil
+}
+
+// selectJoinColumns renames the columns of the struct fields given in the selections
+// of the given join clause to the qualified columns of the joined table, e.g. from
+// "programming_language" to "developer.programming_language". The parameters given
+// in the request to filter or sort by the fields and the columns given in the options,
+// e.g. OrderByAsc or NullsFirst, are kept. The columns already used in the predicates
+// of the keyset pagination, the search, the full-text search or the soft delete
+// cannot be renamed.
+func (p *paginator) selectJoinColumns(join joinClause) error {
+	fields := make([]string, 0, len(join.selections))
+	for field := range join.selections {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	renames := make(map[string]string, len(fields))
+	for _, field := range fields {
+		column := join.selections[field]
+		i := indexOf(field, p.fields)
+		if i == -1 {
+			return fmt.Errorf("paginate: given field %q in join clause does not exist in table %s", field, p.name)
+		}
+		if isStringIn(p.cols[i], p.ids) {
+			return fmt.Errorf("paginate: the id field %q cannot be selected from a joined table", field)
+		}
+		if !sqlFunctionRegexp.MatchString(column) {
+			return fmt.Errorf("paginate: invalid column name %q in join clause", column)
+		}
+		_, fullTextSearch := p.fullTextSearchWeights[p.cols[i]]
+		if hasOrderByColumn(p.keysetClauses, p.cols[i]) || isStringIn(p.cols[i], p.searchColumns) || fullTextSearch || p.cols[i] == p.softDeleteColumn {
+			return fmt.Errorf("paginate: the field %q cannot be selected from a joined table since its column is used by the keyset pagination, search or soft delete", field)
+		}
+		renames[p.cols[i]] = join.targetTable + "." + column
+	}
+
+	// The slices and maps might be shared with the cached tableMetadata,
+	// so they are copied instead of modified.
+	rename := func(names []string) []string {
+		renamed := make([]string, len(names))
+		for i, name := range names {
+			if to, ok := renames[name]; ok {
+				name = to
+			}
+			renamed[i] = name
+		}
+		return renamed
+	}
+	p.cols = rename(p.cols)
+	p.selectedCols = rename(p.selectedCols)
+	p.filters = rename(p.filters)
+	p.groupBy = rename(p.groupBy)
+	p.nullsFirst = rename(p.nullsFirst)
+	if to, ok := renames[p.lastModifiedColumn]; ok {
+		p.lastModifiedColumn = to
+	}
+
+	orderByClauses := make(customOrderByClauses, len(p.orderByClauses))
+	for i, clause := range p.orderByClauses {
+		if to, ok := renames[clause.column]; ok && !clause.expression {
+			clause.column = to
+		}
+		if to, ok := renames[clause.caseColumn]; ok {
+			clause.caseColumn = to
+		}
+		orderByClauses[i] = clause
+	}
+	p.orderByClauses = orderByClauses
+
+	functions := make(map[string]string, len(p.functions))
+	for name, function := range p.functions {
+		if to, ok := renames[name]; ok {
+			name = to
+		}
+		functions[name] = function
+	}
+	p.functions = functions
+
+	parameters := make(parameters, len(p.parameters))
+	for i, param := range p.parameters {
+		if to, ok := renames[param.name]; ok {
+			param.name = to
+		}
+		if param.name == p.sortParam {
+			directives := strings.Split(param.value, ",")
+			for j, d := range directives {
+				if len(d) < 2 {
+					continue
+				}
+				if to, ok := renames[d[1:]]; ok {
+					directives[j] = d[:1] + to
+				}
+			}
+			param.value = strings.Join(directives, ",")
+		}
+		parameters[i] = param
+	}
+	p.parameters = parameters
 
-		if !isStringIn(v.column, p.cols) {
-			return fmt.Errorf("paginate: given column %s in inner clause does not exist in table %s", v.column, p.name)
+	return nil
+}
+
+// validateRawJoinClause returns an error if the given join clause of a RawJoinClause
+// does not start with a join keyword, has more than one sql statement, has a number
+// of placeholders different from the number of its arguments or is conditional.
+func validateRawJoinClause(join joinClause, conditional bool) error {
+	if conditional {
+		return fmt.Errorf("paginate: raw join clauses cannot be conditional")
+	}
+	if !rawJoinRegexp.MatchString(join.raw) {
+		return fmt.Errorf("paginate: raw join clause %q should start with a join keyword", join.raw)
+	}
+	if strings.Contains(join.raw, ";") {
+		return fmt.Errorf("paginate: raw join clause %q cannot contain semicolons", join.raw)
+	}
+	occurrences := countPlaceholders(join.raw, join.dialect)
+	if occurrences == 0 && len(join.args) > 0 {
+		return fmt.Errorf("paginate: cannot receive arguments when placeholders are not defined")
+	}
+	if occurrences > 0 && occurrences != len(join.args) {
+		return fmt.Errorf("paginate: the number of placeholders and arguments in the join clause should be the same")
+	}
+	return nil
+}
+
+// joinsClause returns the active join clauses joined with spaces and
+// their arguments, or an empty string if there are no active joins.
+func (p *paginator) joinsClause() (string, []interface{}) {
+	clause := ""
+	args := make([]interface{}, 0)
+	for _, join := range p.activeJoins() {
+		clause += " " + join.render(p.quoteIdentifiers)
+		args = append(args, join.args...)
+	}
+	return clause, args
+}
+
+// qualifiedTableRegexp matches the table names qualifying the columns referenced
+// in the clauses of the sql command, e.g. "developer" in developer.employee_id.
+var qualifiedTableRegexp = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.`)
+
+// activeJoins returns the join clauses that should be added to the sql command:
+// all the join clauses except the conditional ones whose joined table is not
+// referenced in the selected columns, the filters and sort directives of the
+// request, or the where, having, GROUP BY or custom "ORDER BY" clauses.
+func (p *paginator) activeJoins() []joinClause {
+	references := make([]string, 0, len(p.predicates)+len(p.havingPredicates)+len(p.orderByClauses)+len(p.parameters))
+	for _, predicate := range p.predicates {
+		references = append(references, predicate.predicate)
+	}
+	for _, having := range p.havingPredicates {
+		references = append(references, having.predicate)
+	}
+	for _, clause := range p.orderByClauses {
+		references = append(references, clause.column)
+	}
+	for _, param := range p.parameters {
+		if param.name != p.sortParam {
+			references = append(references, param.name)
 		}
+	}
+	for _, clause := range getSortClauses(p.parameters, p.sortParam, p.cols) {
+		references = append(references, clause.column)
+	}
+	references = append(references, p.selectedCols...)
+	references = append(references, p.groupBy...)
 
-		s := fmt.Sprintf("JOIN %s ON %s.%s = %s.%s", v.targetTable, p.name, v.column, v.targetTable, v.targetColumn)
+	tables := make(map[string]bool)
+	for _, match := range qualifiedTableRegexp.FindAllStringSubmatch(strings.Join(references, " "), -1) {
+		tables[match[1]] = true
+	}
+
+	joins := make([]joinClause, 0, len(p.joins))
+	for _, join := range p.joins {
+		if join.conditional && !tables[join.targetTable] {
+			continue
+		}
+		joins = append(joins, join)
+	}
+	return joins
+}
 
-		if isStringIn(s, p.joins) {
-			return fmt.Errorf("paginate: given join clause %q was already given", s)
+// addSearchClause adds a where clause in p.predicates that matches the
+// search terms given in the p.searchParam request parameter against
+// p.searchColumns. If no search terms are given in the request no where
+// clause is added.
+func (p *paginator) addSearchClause(v url.Values) error {
+	for _, column := range p.searchColumns {
+		if !isStringIn(column, p.cols) {
+			return fmt.Errorf("paginate: given search column %q does not exist in table %s", column, p.name)
 		}
+	}
 
-		p.joins = append(p.joins, s)
+	terms := make([]string, 0)
+	for _, term := range v[p.searchParam] {
+		term = strings.TrimSpace(term)
+		if term == "" || isStringIn(term, terms) {
+			continue
+		}
+		terms = append(terms, term)
+	}
 
+	if len(terms) == 0 {
 		return nil
+	}
 
-	default:
-		return fmt.Errorf("paginate: unkown given type %T", clause)
+	p.predicates = append(p.predicates, createSearchClause(p.dialect, p.searchColumns, terms, p.quoteIdentifiers))
+	return nil
+}
+
+// addKeysetClause adds the "where" clause that fetches the records that come after
+// the continuation token given in the request parameter p.cursorParam.
+func (p *paginator) addKeysetClause(v url.Values) error {
+	for _, clause := range p.orderByClauses {
+		if clause.expression {
+			return fmt.Errorf("paginate: keyset pagination cannot be used with \"ORDER BY\" expressions")
+		}
+	}
+	clauses := mergeOrderByClauses(p.orderByClauses, getSortClauses(p.parameters, p.sortParam, p.cols))
+	for _, id := range p.ids {
+		if !hasOrderByColumn(clauses, id) {
+			clauses = append(clauses, orderByClause{column: id, sorting: "ASC"})
+		}
+	}
+	nulls := p.nullsOrdering()
+	for i, clause := range clauses {
+		if !isStringIn(clause.column, p.selectedCols) {
+			return fmt.Errorf("paginate: keyset pagination requires the sorting column %q to be selected", clause.column)
+		}
+		if clause.nulls == "" {
+			clauses[i].nulls = nulls[clause.column]
+		}
+	}
+	p.keysetClauses = clauses
+
+	// The records are always fetched from the continuation token onwards.
+	p.pageNumber = defaultPageNumber
+	p.hasOffset = false
+
+	token := v.Get(p.cursorParam)
+	if token == "" {
+		return nil
+	}
+
+	values, err := decodeCursor(token)
+	if err != nil || len(values) != len(clauses) {
+		return fmt.Errorf("paginate: invalid continuation token %q", token)
+	}
+
+	p.predicates = append(p.predicates, createKeysetClause(p.dialect, clauses, values, p.nullableColumns(), p.quoteIdentifiers))
+	return nil
+}
+
+// nextCursor returns the continuation token with the values of the
+// "ORDER BY" columns of the last row.
+func (p *paginator) nextCursor() string {
+	row := reflect.ValueOf(p.lastRow)
+	values := make([]interface{}, 0, len(p.keysetClauses))
+	for _, clause := range p.keysetClauses {
+		var value interface{}
+		if i := indexOf(clause.column, p.selectedCols); i >= 0 {
+			value = row.FieldByName(p.selectedFields[i]).Interface()
+		}
+		values = append(values, value)
+	}
+	return encodeCursor(values)
+}
+
+// validateGroupBy returns an error if any of the columns given with the GroupBy
+// option is not a column of the table. This prevents sql injection through the
+// column names.
+func (p *paginator) validateGroupBy() error {
+	for _, column := range p.groupBy {
+		if !isStringIn(column, p.cols) {
+			return fmt.Errorf("paginate: cannot group by column %q since it does not exist in table %s", column, p.name)
+		}
+	}
+	return nil
+}
+
+// groupByClause returns the sql GROUP BY clause with the columns given with
+// the GroupBy option, or with all the columns of the table when the Distinct
+// option is used, or an empty string if there are no columns.
+func (p *paginator) groupByClause() string {
+	groupBy := p.groupBy
+
+	// Distinct records are counted grouping them by all the selected columns.
+	if len(groupBy) == 0 && p.distinct {
+		groupBy = p.selectedCols
+	}
+
+	if len(groupBy) == 0 {
+		return ""
 	}
+
+	cols := groupBy
+	if len(p.activeJoins()) > 0 || p.quoteIdentifiers {
+		cols = make([]string, 0, len(groupBy))
+		for _, c := range groupBy {
+			cols = append(cols, p.column(c))
+		}
+	}
+	return " GROUP BY " + strings.Join(cols, ", ")
+}
+
+// havingClause returns the sql HAVING clause with the predicates added with
+// AddHavingClause joined with AND and their arguments, or an empty string if
+// there are no predicates. When there are several predicates every one of them
+// is wrapped in parentheses, so an OR in one predicate cannot bypass the others.
+func (p *paginator) havingClause() (string, []interface{}) {
+	if len(p.havingPredicates) == 0 {
+		return "", nil
+	}
+
+	predicates := make([]string, 0, len(p.havingPredicates))
+	args := make([]interface{}, 0)
+	for _, having := range p.havingPredicates {
+		predicate := having.predicate
+		if len(p.havingPredicates) > 1 {
+			predicate = parenthesize(predicate)
+		}
+		predicates = append(predicates, predicate)
+		args = append(args, having.args...)
+	}
+	return " HAVING " + strings.Join(predicates, " AND "), args
+}
+
+// validateOrderByClauses returns an error if the column of any of the custom
+// "ORDER BY" clauses given with OrderByAsc or OrderByDesc is not a column of
+// the table. This prevents sql injection through the column names.
+func (p *paginator) validateOrderByClauses() error {
+	for _, clause := range append(append(customOrderByClauses{}, p.orderByClauses...), p.defaultOrderByClauses...) {
+		if clause.caseColumn != "" && !isStringIn(clause.caseColumn, p.cols) {
+			return fmt.Errorf("paginate: cannot sort by column %q since it does not exist in table %s", clause.caseColumn, p.name)
+		}
+		if clause.expression {
+			continue
+		}
+		if !isStringIn(clause.column, p.cols) {
+			return fmt.Errorf("paginate: cannot sort by column %q since it does not exist in table %s", clause.column, p.name)
+		}
+	}
+	return nil
+}
+
+// addFullTextSearchClauses adds the predicate and the "ORDER BY" clause of the
+// full-text search when the request has a non-empty fullTextSearchParam value.
+func (p *paginator) addFullTextSearchClauses(v url.Values) error {
+	columns := make([]string, 0, len(p.fullTextSearchWeights))
+	for column := range p.fullTextSearchWeights {
+		if !isStringIn(column, p.cols) {
+			return fmt.Errorf("paginate: given full-text search column %q does not exist in table %s", column, p.name)
+		}
+		columns = append(columns, column)
+	}
+
+	// Map iteration order is random, so we sort the columns
+	// to always produce the same sql command.
+	sort.Strings(columns)
+
+	terms := make([]string, 0)
+	for _, term := range v[p.fullTextSearchParam] {
+		term = strings.TrimSpace(term)
+		if term == "" || isStringIn(term, terms) {
+			continue
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Repeated parameters will match records containing any of the queries,
+	// or all of them with plainto_tsquery, which has no OR operator.
+	tsquery, query := "websearch_to_tsquery", strings.Join(terms, " or ")
+	if p.fullTextSearchPlain {
+		tsquery, query = "plainto_tsquery", strings.Join(terms, " ")
+	}
+	predicate, rank := createFullTextSearchClauses(columns, p.fullTextSearchWeights, tsquery, query)
+	p.predicates = append(p.predicates, predicate)
+	p.orderByClauses = append(p.orderByClauses, rank)
+	return nil
 }
@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+package paginate
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/url"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{
+			{int64(1), "Ringo", int64(2)},
+			{int64(2), "John", int64(2)},
+		},
+	)
+	defer db.Close()
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = pag.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	// The type should match the table of the paginator.
+	type Manager struct {
+		ID int `paginate:"id"`
+	}
+	if _, err = Collect[Manager](pag); err == nil {
+		t.Error("expected an error when collecting rows of a different type")
+	}
+
+	employees, err := Collect[Employee](pag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(employees) != 2 || employees[0].Name != "Ringo" || employees[1].Name != "John" {
+		t.Errorf("expected Ringo and John; got %+v", employees)
+	}
+	if res := pag.Response(); res.PageCount != 2 || res.TotalSize != 2 {
+		t.Errorf("expected a page count and total size of 2; got %+v", res)
+	}
+
+	if _, err = Collect[Employee](pag); err != ErrPaginatorIsClosed {
+		t.Errorf("expected ErrPaginatorIsClosed; got %v", err)
+	}
+}
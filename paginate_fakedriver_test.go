@@ -0,0 +1,157 @@
+package paginate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+// fakeConnector is a driver.Connector that returns the predefined rows
+// for any query. We use it to test the scanning behaviors of Paginator
+// without a real database. Every executed query is recorded in queries.
+// Count queries (SELECT count(*) ...) will return the count value or
+// countErr if it is set, while the other queries will return rowsErr if it
+// is set. If blockRows (or blockCount) is true the other queries (or the
+// count queries) will block until their context is done and the context
+// error will be recorded in cancelled.
+type fakeConnector struct {
+	mu         sync.Mutex
+	columns    []string
+	rows       [][]driver.Value
+	count      int64
+	countErr   error
+	rowsErr    error
+	blockRows  bool
+	blockCount bool
+	cancelled  error
+	queries    []string
+	args       [][]driver.Value
+}
+
+// newFakeDB returns an *sql.DB that returns the given rows for any query.
+func newFakeDB(columns []string, rows [][]driver.Value) (*sql.DB, *fakeConnector) {
+	c := &fakeConnector{columns: columns, rows: rows}
+	return sql.OpenDB(c), c
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{c: c}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+func (c *fakeConnector) executedQueries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.queries...)
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fakedriver: use newFakeDB instead")
+}
+
+type fakeConn struct {
+	c *fakeConnector
+}
+
+func (conn *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: conn.c, query: query}, nil
+}
+
+func (conn *fakeConn) Close() error {
+	return nil
+}
+
+func (conn *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakedriver: transactions are not supported")
+}
+
+type fakeStmt struct {
+	c     *fakeConnector
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("fakedriver: exec is not supported")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.run(context.Background(), args)
+}
+
+func (s *fakeStmt) QueryContext(ctx context.Context, named []driver.NamedValue) (driver.Rows, error) {
+	args := make([]driver.Value, 0, len(named))
+	for _, nv := range named {
+		args = append(args, nv.Value)
+	}
+	return s.run(ctx, args)
+}
+
+func (s *fakeStmt) run(ctx context.Context, args []driver.Value) (driver.Rows, error) {
+	s.c.mu.Lock()
+	s.c.queries = append(s.c.queries, s.query)
+	s.c.args = append(s.c.args, args)
+	isCount := strings.HasPrefix(s.query, "SELECT count(*) FROM")
+	block := s.c.blockRows && !isCount || s.c.blockCount && isCount
+	s.c.mu.Unlock()
+
+	if block {
+		<-ctx.Done()
+		s.c.mu.Lock()
+		s.c.cancelled = ctx.Err()
+		s.c.mu.Unlock()
+		return nil, ctx.Err()
+	}
+
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	if isCount {
+		if s.c.countErr != nil {
+			return nil, s.c.countErr
+		}
+		return &fakeRows{columns: []string{"count"}, rows: [][]driver.Value{{s.c.count}}}, nil
+	}
+	if s.c.rowsErr != nil {
+		return nil, s.c.rowsErr
+	}
+	return &fakeRows{columns: s.c.columns, rows: s.c.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	_ "github.com/lib/pq"
 	"log"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -57,6 +58,11 @@ CREATE TABLE employees
 	 null_date     TIMESTAMP NULL,
 	 null_int      INT NULL,
 	 null_float    FLOAT NULL,
+	 null_smallint SMALLINT NULL,
+	 null_bytes    BLOB NULL,
+	 null_numeric  NUMERIC(10,2) NULL,
+     tenant_id     INT NOT NULL DEFAULT 1,
+     ` + "`order`" + `       INT NOT NULL DEFAULT 0,
      CONSTRAINT employee_worker_number_uindex UNIQUE (worker_number)
   );
 `
@@ -74,6 +80,7 @@ CREATE UNIQUE INDEX developer_employee_id_uindex ON developer (employee_id);
 CREATE TABLE manager
   (
      employee_id INT NOT NULL,
+     tenant_id   INT NOT NULL DEFAULT 1,
      FOREIGN KEY (employee_id) REFERENCES employees (id) ON DELETE CASCADE
   );
 
@@ -135,7 +142,14 @@ CREATE TABLE employees
      null_bool     BOOLEAN,
      null_date     TIMESTAMP WITH time zone,
      null_int      INTEGER,
-     null_float    DOUBLE PRECISION
+     null_float    DOUBLE PRECISION,
+     null_smallint SMALLINT,
+     null_bytes    BYTEA,
+     null_numeric  NUMERIC(10,2),
+     tenant_id     INTEGER NOT NULL DEFAULT 1,
+     "order"       INTEGER NOT NULL DEFAULT 0,
+     meta          JSONB NOT NULL DEFAULT '{}',
+     tags          TEXT[] NOT NULL DEFAULT '{}'
   );
 
 CREATE UNIQUE INDEX employees_id_uindex
@@ -163,7 +177,8 @@ CREATE TABLE manager
   (
      employee_id BIGINT NOT NULL CONSTRAINT manager_employees_id_fk REFERENCES
      employees ON DELETE
-     CASCADE
+     CASCADE,
+     tenant_id   INTEGER NOT NULL DEFAULT 1
   );
 
 CREATE UNIQUE INDEX manager_employee_id_uindex ON manager (employee_id); 
@@ -337,7 +352,9 @@ func addDataToDatabaseTables(db *sql.DB, dialect string) error {
 	}
 
 	// Let's create 5 developers now. The first 3 will be Go developers.
-	// The rest 2 will be Python developers.
+	// The rest 2 will be Python developers. Developers also have their
+	// programming language as raw bytes in the column "null_bytes" and
+	// a bonus of 12345678.91 in the column "null_numeric".
 	for i := 0; i < len(firstFiveIDs); i++ {
 		var programmingLanguage string
 
@@ -356,6 +373,13 @@ func addDataToDatabaseTables(db *sql.DB, dialect string) error {
 				}
 				return err
 			}
+			_, err = tx.Exec("UPDATE employees SET null_bytes = ?, null_numeric = ?, `order` = 1 WHERE id = ?;", []byte(programmingLanguage), "12345678.91", firstFiveIDs[i])
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return err
+				}
+				return err
+			}
 		case "postgres":
 			_, err := tx.Exec("INSERT INTO developer (employee_id, programming_language) VALUES ($1, $2);", firstFiveIDs[i], programmingLanguage)
 			if err != nil {
@@ -364,6 +388,17 @@ func addDataToDatabaseTables(db *sql.DB, dialect string) error {
 				}
 				return err
 			}
+			// Postgres developers also have their role and team in the jsonb column "meta".
+			// Their programming language is also one of the tags of the text[] column "tags".
+			meta := fmt.Sprintf(`{"role": "developer", "team": {"language": %q}}`, programmingLanguage)
+			tags := fmt.Sprintf("{developer,%s}", strings.ToLower(programmingLanguage))
+			_, err = tx.Exec(`UPDATE employees SET meta = $1, tags = $2, null_bytes = $3, null_numeric = $4, "order" = 1 WHERE id = $5;`, meta, tags, []byte(programmingLanguage), "12345678.91", firstFiveIDs[i])
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return err
+				}
+				return err
+			}
 		}
 	}
 
@@ -386,6 +421,13 @@ func addDataToDatabaseTables(db *sql.DB, dialect string) error {
 				}
 				return err
 			}
+			_, err = tx.Exec(`UPDATE employees SET meta = '{"role": "manager"}', tags = '{manager}' WHERE id = $1;`, id)
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return err
+				}
+				return err
+			}
 		}
 	}
 
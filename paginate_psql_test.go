@@ -1,10 +1,12 @@
 package paginate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/url"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -1622,7 +1624,7 @@ func ExampleTest_NewPaginatorPsql_With_Custom_OrderByDesc_Clause_With_ID() {
 	fmt.Println(cmd)
 	fmt.Printf("args length: %v\n", len(args))
 	// Output:
-	// SELECT id, name, last_name, worker_number, date_joined, salary, null_text, null_varchar, null_bool, null_date, null_int, null_float, count(*) over() FROM employees ORDER BY id LIMIT 30 OFFSET 0
+	// SELECT id, name, last_name, worker_number, date_joined, salary, null_text, null_varchar, null_bool, null_date, null_int, null_float, count(*) over() FROM employees ORDER BY id DESC LIMIT 30 OFFSET 0
 	// args length: 0
 }
 
@@ -1673,7 +1675,7 @@ func Test_InnerJoin_Psql_Query_String_HappyPath(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedSql := "SELECT id, name, last_name, count(*) over() FROM employees JOIN managers ON employees.id = managers.employee_id WHERE name ILIKE $1 ORDER BY id LIMIT 30 OFFSET 0"
+	expectedSql := "SELECT employees.id, employees.name, employees.last_name, count(*) over() FROM employees JOIN managers ON employees.id = managers.employee_id WHERE (name ILIKE $1) ORDER BY id DESC LIMIT 30 OFFSET 0"
 	expectedArg := "%ringo%"
 
 	if sql != expectedSql {
@@ -1781,9 +1783,9 @@ func Test_InnerJoin_Psql_Employees_That_Are_Managers(t *testing.T) {
 func Test_InnerJoin_Psql_Employees_That_Are_Go_Developers(t *testing.T) {
 	// ProgrammingLanguage it is a column from the joined table ("developer").
 	// In this test we are able to prove that it is possible to filter
-	// joined columns. However, if there are clashes with the column names
-	// between two or more joined tables, to get the correct name of the columns
-	// it's a bit hard. I need to think more about how to smooth this problem.
+	// joined columns. Since the columns of the paginated table are qualified
+	// with the table name when there are join clauses, the columns of the
+	// joined tables should be qualified as well with the "col" tag.
 	type Employee struct {
 		ID                  int       `paginate:"id;col=id"`
 		Name                string    `paginate:"col=name"`
@@ -1791,7 +1793,7 @@ func Test_InnerJoin_Psql_Employees_That_Are_Go_Developers(t *testing.T) {
 		WorkNumber          int64     `paginate:"col=worker_number"`
 		DateJoined          time.Time `paginate:"col=date_joined"`
 		Salary              float64   `paginate:"col=salary"`
-		ProgrammingLanguage string    `paginate:"filter;col=programming_language;param=lg"`
+		ProgrammingLanguage string    `paginate:"filter;col=developer.programming_language;param=lg"`
 	}
 
 	u, err := url.Parse("http://localhost?lg=Go")
@@ -1889,6 +1891,114 @@ func Test_InnerJoin_Psql_Employees_That_Are_Go_Developers(t *testing.T) {
 	}
 }
 
+func Test_InnerJoin_Psql_Select_Joined_Columns(t *testing.T) {
+	// ProgrammingLanguage is mapped to the column of the joined table
+	// ("developer") with InnerJoin.Select instead of the "col" tag, so it
+	// is still filtered with the request parameter "lg".
+	type Employee struct {
+		ID                  int    `paginate:"id;col=id"`
+		Name                string `paginate:"col=name"`
+		ProgrammingLanguage string `paginate:"filter;param=lg"`
+	}
+
+	u, err := url.Parse("http://localhost?lg=Go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	innerClause, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	innerClause.On("id", "developer", "employee_id").
+		Select(map[string]string{"ProgrammingLanguage": "programming_language"})
+
+	err = pag.AddJoinClause(innerClause)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if employee.ProgrammingLanguage != "Go" {
+			t.Errorf("All developers should be gophers; got %q", employee.ProgrammingLanguage)
+		}
+		names = append(names, employee.Name)
+	}
+
+	expected := []string{employees[0].Name, employees[1].Name, employees[2].Name}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected the Go developers to be %v; got %v", expected, names)
+	}
+}
+
+func Test_InnerJoin_Psql_Filter_Clashing_Column(t *testing.T) {
+	// Both employees and manager have a tenant_id column, so the filtered
+	// column should be qualified with the name of the paginated table.
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		TenantID int    `paginate:"filter;col=tenant_id"`
+	}
+
+	u, err := url.Parse("http://localhost?tenant_id=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	innerClause, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	innerClause.On("id", "manager", "employee_id")
+
+	err = pag.AddJoinClause(innerClause)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+
+	if count != 5 {
+		t.Errorf("we should have 5 managers; got %d", count)
+	}
+}
+
 func Test_InnerJoin_Psql_Employees_That_Are_Python_Developers_With_Pagination(t *testing.T) {
 	type Employee struct {
 		ID                  int       `paginate:"id;col=id"`
@@ -1897,7 +2007,7 @@ func Test_InnerJoin_Psql_Employees_That_Are_Python_Developers_With_Pagination(t
 		WorkNumber          int64     `paginate:"col=worker_number"`
 		DateJoined          time.Time `paginate:"col=date_joined"`
 		Salary              float64   `paginate:"col=salary"`
-		ProgrammingLanguage string    `paginate:"filter;col=programming_language;param=lg"`
+		ProgrammingLanguage string    `paginate:"filter;col=developer.programming_language;param=lg"`
 	}
 
 	u, err := url.Parse("http://localhost?lg=Python&page=2")
@@ -1970,3 +2080,2315 @@ func Test_InnerJoin_Psql_Employees_That_Are_Python_Developers_With_Pagination(t
 		t.Fatalf("expected to have %s python developer; got %s", expectedPythonDeveloper.Name, resultPythonDeveloper.Name)
 	}
 }
+
+func Test_LeftJoin_Psql_Employees_With_Optional_Developer_Row(t *testing.T) {
+	// ProgrammingLanguage is a column from the joined table ("developer").
+	// Since we are using a LEFT JOIN, employees who are not developers should
+	// still appear in the results with the zero value of ProgrammingLanguage.
+	type Employee struct {
+		ID                  int    `paginate:"id;col=id"`
+		Name                string `paginate:"col=name"`
+		LastName            string `paginate:"col=last_name"`
+		ProgrammingLanguage string `paginate:"col=developer.programming_language"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leftClause, err := NewLeftJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leftClause.On("id", "developer", "employee_id")
+
+	err = pag.AddJoinClause(leftClause)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sql, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSql := "SELECT employees.id, employees.name, employees.last_name, developer.programming_language, count(*) over() FROM employees LEFT JOIN developer ON employees.id = developer.employee_id ORDER BY id LIMIT 30 OFFSET 0"
+	if sql != expectedSql {
+		t.Errorf("expected sql %q; got %q instead", expectedSql, sql)
+	}
+
+	rows, err := psqlTestDB.Query(sql, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	if len(results) != len(employees) {
+		t.Fatalf("expected to have %d results since no employee should be dropped; got %d", len(employees), len(results))
+	}
+
+	expectedLanguages := []string{"Go", "Go", "Go", "Python", "Python", "", "", "", "", ""}
+
+	for i, r := range results {
+		if r.Name != employees[i].Name {
+			t.Errorf("expected employee name to be %s; got %s", employees[i].Name, r.Name)
+		}
+		if r.ProgrammingLanguage != expectedLanguages[i] {
+			t.Errorf("expected programming language of %s to be %q; got %q", r.Name, expectedLanguages[i], r.ProgrammingLanguage)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_Search_Multiple_Terms_ILIKE_ANY(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		LastName string `paginate:"col=last_name"`
+	}
+
+	u, err := url.Parse("http://localhost?q=smith&q=gates")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), Search("q", "name", "last_name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	if len(results) != 6 {
+		t.Errorf("we should have 6 records in result; got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.LastName != "Smith" && r.LastName != "Gates" {
+			t.Errorf("expected only employees whose last name is Smith or Gates; got %s", r.LastName)
+		}
+	}
+}
+
+func Test_InnerJoin_Psql_Employees_That_Are_Managers_With_Multiple_Conditions(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		LastName string `paginate:"col=last_name"`
+		TenantID int    `paginate:"col=tenant_id"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	innerClause, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	innerClause.On("id", "manager", "employee_id").AndOn("tenant_id", "tenant_id")
+
+	err = pag.AddJoinClause(innerClause)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sql, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSql := "SELECT employees.id, employees.name, employees.last_name, employees.tenant_id, count(*) over() FROM employees " +
+		"JOIN manager ON employees.id = manager.employee_id AND employees.tenant_id = manager.tenant_id " +
+		"ORDER BY id LIMIT 30 OFFSET 0"
+	if sql != expectedSql {
+		t.Errorf("expected sql %q; got %q instead", expectedSql, sql)
+	}
+
+	rows, err := psqlTestDB.Query(sql, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	managers := employees[5:]
+
+	if len(results) != len(managers) {
+		t.Fatalf("expected to have %[1]d results since there are %[1]d managers; got %d", len(managers), len(results))
+	}
+
+	for i, r := range results {
+		if r.Name != managers[i].Name {
+			t.Errorf("expected manager name to be %s; got %s", managers[i].Name, r.Name)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_NullsLastByDefault(t *testing.T) {
+	type Employee struct {
+		ID       int      `paginate:"id;col=id"`
+		Name     string   `paginate:"col=name"`
+		NullBool NullBool `paginate:"col=null_bool"`
+		NullInt  NullInt  `paginate:"col=null_int"`
+	}
+
+	// Without the option postgres would sort the NULL values first in this case.
+	u, err := url.Parse("http://localhost?sort=-null_bool,-null_int")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), NullsLastByDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	if len(results) != 10 {
+		t.Fatalf("we should have 10 records in result; got %d", len(results))
+	}
+
+	// Only Bill and Fred have a non-null value in the null_bool column.
+	for i, r := range results {
+		if i < 2 && !r.NullBool.Valid {
+			t.Errorf("expected the non-null values to be sorted first; got a null value in position %d", i)
+		}
+		if i >= 2 && r.NullBool.Valid {
+			t.Errorf("expected the null values to be sorted last; got a non-null value in position %d", i)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_Scan_Pointer_Fields(t *testing.T) {
+	type Employee struct {
+		ID       int     `paginate:"id;col=id"`
+		Name     *string `paginate:"col=name"`
+		NullBool *bool   `paginate:"col=null_bool"`
+		NullInt  *int    `paginate:"col=null_int"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	nonNullBools := 0
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if employee.Name == nil {
+			t.Errorf("expected employee %d to have a name", employee.ID)
+		}
+		if employee.NullInt != nil {
+			t.Errorf("expected a nil pointer for the NULL value of employee %d; got %d", employee.ID, *employee.NullInt)
+		}
+		if employee.NullBool != nil {
+			nonNullBools++
+		}
+	}
+
+	// Only Bill and Fred have a non-null value in the null_bool column.
+	if nonNullBools != 2 {
+		t.Errorf("we should have 2 non-null values in null_bool; got %d", nonNullBools)
+	}
+}
+
+func TestNewPaginatorPsql_Scan_Null_Smallint(t *testing.T) {
+	type Employee struct {
+		ID           int   `paginate:"id;col=id"`
+		NullSmallint int16 `paginate:"col=null_smallint"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	if len(results) != 10 {
+		t.Fatalf("we should have 10 records in result; got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.NullSmallint != 0 {
+			t.Errorf("expected the NULL smallint of employee %d to be zero; got %d", r.ID, r.NullSmallint)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_OrderBySeededRandom(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id;col=id"`
+		Name string `paginate:"col=name"`
+	}
+
+	// fetchIDs returns the ids of the employees of the given page sorted with the given seed.
+	fetchIDs := func(seed int64, page int) []int {
+		u, err := url.Parse(fmt.Sprintf("http://localhost?page=%d&page_size=5", page))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), OrderBySeededRandom(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]int, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, employee.ID)
+		}
+		return ids
+	}
+
+	first := append(fetchIDs(12345, 1), fetchIDs(12345, 2)...)
+	second := append(fetchIDs(12345, 1), fetchIDs(12345, 2)...)
+	other := append(fetchIDs(67890, 1), fetchIDs(67890, 2)...)
+
+	if len(first) != 10 {
+		t.Fatalf("we should have 10 records across both pages; got %d", len(first))
+	}
+
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("expected the same seed to produce the same order; got %v and %v", first, second)
+	}
+
+	if fmt.Sprint(first) == fmt.Sprint(other) {
+		t.Errorf("expected different seeds to produce different orders; got %v for both", first)
+	}
+
+	seen := make(map[int]bool)
+	for _, id := range first {
+		if seen[id] {
+			t.Errorf("expected the order to be stable across pages; got id %d twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestNewPaginatorPsql_Scan_Uint_Fields(t *testing.T) {
+	type Employee struct {
+		ID           uint   `paginate:"id;col=id"`
+		WorkerNumber uint32 `paginate:"col=worker_number"`
+		NullSmallint uint16 `paginate:"col=null_smallint"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	if len(results) != 10 {
+		t.Fatalf("we should have 10 records in result; got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.ID == 0 {
+			t.Errorf("expected employee with worker number %d to have an id", r.WorkerNumber)
+		}
+		if r.WorkerNumber == 0 {
+			t.Errorf("expected employee %d to have a worker number", r.ID)
+		}
+		if r.NullSmallint != 0 {
+			t.Errorf("expected the NULL smallint of employee %d to be zero; got %d", r.ID, r.NullSmallint)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_RequestParameter_Between_Clause(t *testing.T) {
+	type Employee struct {
+		ID     int     `paginate:"id;col=id"`
+		Name   string  `paginate:"col=name"`
+		Salary float64 `paginate:"filter;col=salary"`
+	}
+
+	u, err := url.Parse("http://localhost?salary=4000..8000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	// Only Bill and Rob have salaries out of the given range.
+	if len(results) != 8 {
+		t.Fatalf("we should have 8 records in result; got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.Salary < 4000 || r.Salary > 8000 {
+			t.Errorf("expected salaries between 4000 and 8000; got %v", r.Salary)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_FullTextSearch_Weighted_Ranking(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		LastName string `paginate:"col=last_name"`
+	}
+
+	u, err := url.Parse("http://localhost?q=rob&q=smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weights := map[string]string{"name": "A", "last_name": "B"}
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), FullTextSearch("q", weights))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	// Rob Williams and the five Smiths should match the search.
+	if len(results) != 6 {
+		t.Fatalf("we should have 6 records in result; got %d", len(results))
+	}
+
+	// Rob matches in the name column which has a higher weight than
+	// the last_name column, so he should be ranked first.
+	if results[0].Name != "Rob" {
+		t.Errorf("expected Rob to be ranked first; got %s %s", results[0].Name, results[0].LastName)
+	}
+
+	for _, r := range results[1:] {
+		if r.LastName != "Smith" {
+			t.Errorf("expected the rest of the results to be Smiths; got %s %s", r.Name, r.LastName)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_RequestParameter_Contains_Clause(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		LastName string `paginate:"filter;col=last_name"`
+	}
+
+	u, err := url.Parse("http://localhost?last_name=~MIT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	// The match should be case-insensitive, so we should get all the Smiths.
+	if len(results) != 5 {
+		t.Fatalf("we should have 5 records in result; got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.LastName != "Smith" {
+			t.Errorf("expected last name to be Smith; got %s", r.LastName)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_RequestParameter_Not_Contains_Clause(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		LastName string `paginate:"filter;col=last_name"`
+	}
+
+	u, err := url.Parse("http://localhost?last_name=!~MIT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	// The match should be case-insensitive, so we should get everybody but the Smiths.
+	if len(results) != 5 {
+		t.Fatalf("we should have 5 records in result; got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.LastName == "Smith" {
+			t.Errorf("expected last name not to be Smith; got %s", r.LastName)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_RequestParameter_IS_NULL_And_IS_NOT_NULL_Clauses(t *testing.T) {
+	type Employee struct {
+		ID       int      `paginate:"id;col=id"`
+		Name     string   `paginate:"col=name"`
+		NullBool NullBool `paginate:"filter;col=null_bool"`
+	}
+
+	// fetch returns the employees filtered with the given value of null_bool.
+	fetch := func(value string) []Employee {
+		u, err := url.Parse("http://localhost?null_bool=" + value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		results := make([]Employee, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, employee)
+		}
+		return results
+	}
+
+	// Only Bill and Fred have a non-null value in the null_bool column.
+	notNull := fetch("notnull")
+	if len(notNull) != 2 {
+		t.Fatalf("we should have 2 records in result; got %d", len(notNull))
+	}
+	for _, r := range notNull {
+		if !r.NullBool.Valid {
+			t.Errorf("expected %s to have a non-null value", r.Name)
+		}
+	}
+
+	null := fetch("null")
+	if len(null) != 8 {
+		t.Fatalf("we should have 8 records in result; got %d", len(null))
+	}
+	for _, r := range null {
+		if r.NullBool.Valid {
+			t.Errorf("expected %s to have a null value", r.Name)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_SetFilterConjunction_OR(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"filter;col=name"`
+		LastName string `paginate:"filter;col=last_name"`
+	}
+
+	u, err := url.Parse("http://localhost?name=Rob&last_name=Gates")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.SetFilterConjunction("OR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	// With AND no employee would match, but with OR we should get Bill Gates and Rob Williams.
+	if len(results) != 2 {
+		t.Fatalf("we should have 2 records in result; got %d", len(results))
+	}
+
+	if results[0].LastName != "Gates" || results[1].Name != "Rob" {
+		t.Errorf("expected Bill Gates and Rob Williams; got %v", results)
+	}
+}
+
+func TestNewPaginatorPsql_Prepare_And_Fetch_Three_Pages(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id;col=id"`
+		Name string `paginate:"col=name"`
+	}
+
+	u, err := url.Parse("http://localhost?page_size=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	prepared, err := pag.Prepare(ctx, psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer prepared.Close()
+
+	seen := make(map[int]bool)
+
+	for page := 1; page <= 3; page++ {
+		p, err := prepared.Page(ctx, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results := make([]Employee, 0)
+		for p.NextData() {
+			employee := Employee{}
+			err = p.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, employee)
+		}
+
+		if len(results) != 3 {
+			t.Fatalf("we should have 3 records in page %d; got %d", page, len(results))
+		}
+
+		for _, r := range results {
+			if seen[r.ID] {
+				t.Errorf("employee %d was returned in more than one page", r.ID)
+			}
+			seen[r.ID] = true
+		}
+
+		res := p.Response()
+		if res.PageNumber != page || res.TotalSize != 10 || !res.HasNextPage {
+			t.Errorf("unexpected pagination response for page %d: %+v", page, res)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_Filter_With_DATE_Function(t *testing.T) {
+	type Employee struct {
+		ID         int       `paginate:"id;col=id"`
+		Name       string    `paginate:"col=name"`
+		DateJoined time.Time `paginate:"filter;col=date_joined;fn=DATE"`
+	}
+
+	// All the employees joined on the same day, so let's get that day first.
+	var day string
+	err := psqlTestDB.QueryRow("SELECT to_char(date_joined, 'YYYY-MM-DD') FROM employees LIMIT 1").Scan(&day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fetch returns the employees that joined on the given day.
+	fetch := func(day string) []Employee {
+		u, err := url.Parse("http://localhost?date_joined=" + day)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		results := make([]Employee, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, employee)
+		}
+		return results
+	}
+
+	if results := fetch(day); len(results) != 10 {
+		t.Errorf("we should have 10 records joined on %s; got %d", day, len(results))
+	}
+
+	if results := fetch("2000-01-01"); len(results) != 0 {
+		t.Errorf("we should have 0 records joined on 2000-01-01; got %d", len(results))
+	}
+}
+
+func TestNewPaginatorPsql_KeysetPagination_Mixed_Sort(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		LastName string `paginate:"col=last_name"`
+	}
+
+	// fetch returns the names of the employees of the page after the given
+	// continuation token and the response of the paginator.
+	fetch := func(cursor string) ([]string, PaginationResponse) {
+		u, err := url.Parse("http://localhost?sort=-last_name,+name&page_size=3&cursor=" + cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), KeysetPagination("cursor"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, employee.Name)
+		}
+		return names, pag.Response()
+	}
+
+	expected := [][]string{
+		{"Rob", "Ringo", "Erika"},
+		{"Fred", "John", "Mark"},
+		{"Rafael", "Maria", "Bill"},
+		{"Juliana"},
+	}
+
+	cursor := ""
+	for i, names := range expected {
+		results, res := fetch(cursor)
+		if fmt.Sprint(results) != fmt.Sprint(names) {
+			t.Errorf("page %d should have the employees %v; got %v", i+1, names, results)
+		}
+		if hasNext := i < len(expected)-1; res.HasNextPage != hasNext || (res.NextCursor != "") != hasNext {
+			t.Errorf("page %d should have a next page: %v; got %+v", i+1, hasNext, res)
+		}
+		cursor = res.NextCursor
+	}
+}
+
+func TestNewPaginatorPsql_KeysetPagination_Nullable_Sort(t *testing.T) {
+	type Employee struct {
+		ID       int      `paginate:"id;col=id"`
+		Name     string   `paginate:"col=name"`
+		NullBool NullBool `paginate:"col=null_bool"`
+	}
+
+	// fetch returns the names of the employees of the page after the given
+	// continuation token and the response of the paginator.
+	fetch := func(cursor string) ([]string, PaginationResponse) {
+		u, err := url.Parse("http://localhost?sort=+null_bool&page_size=3&cursor=" + cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), KeysetPagination("cursor"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = pag.Execute(context.Background(), psqlTestDB)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, employee.Name)
+		}
+		return names, pag.Response()
+	}
+
+	// Only Bill and Fred have a null_bool value, the rest are NULL values
+	// that postgres sorts last.
+	expected := [][]string{
+		{"Bill", "Fred", "Ringo"},
+		{"Mark", "John", "Rob"},
+		{"Juliana", "Erika", "Maria"},
+		{"Rafael"},
+	}
+
+	cursor := ""
+	for i, names := range expected {
+		results, res := fetch(cursor)
+		if fmt.Sprint(results) != fmt.Sprint(names) {
+			t.Errorf("page %d should have the employees %v; got %v", i+1, names, results)
+		}
+		cursor = res.NextCursor
+	}
+}
+
+func TestNewPaginatorPsql_Distinct_With_Left_Join(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		TenantID int    `paginate:"col=tenant_id"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), Distinct())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every employee matches the five managers of the same tenant, so
+	// without DISTINCT we would get five duplicates of every employee.
+	leftClause, err := NewLeftJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leftClause.On("tenant_id", "manager", "tenant_id")
+
+	err = pag.AddJoinClause(leftClause)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sql, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(sql, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	if len(results) != 10 {
+		t.Errorf("we should have 10 distinct employees; got %d", len(results))
+	}
+
+	if res := pag.Response(); res.TotalSize != 10 {
+		t.Errorf("the total size should be 10 distinct employees; got %d", res.TotalSize)
+	}
+}
+
+func Test_RightJoin_Psql_Developers_With_Their_Employee_Row(t *testing.T) {
+	// Since we are using a RIGHT JOIN, the developer table drives the
+	// results, so only the employees who are developers should appear.
+	type Employee struct {
+		ID                  int    `paginate:"id;col=id"`
+		Name                string `paginate:"col=name"`
+		ProgrammingLanguage string `paginate:"col=developer.programming_language"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rightClause, err := NewRightJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rightClause.On("id", "developer", "employee_id")
+
+	err = pag.AddJoinClause(rightClause)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sql, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(sql, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]Employee, 0)
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, employee)
+	}
+
+	expectedLanguages := []string{"Go", "Go", "Go", "Python", "Python"}
+
+	if len(results) != len(expectedLanguages) {
+		t.Fatalf("expected to have %d results, one for each developer; got %d", len(expectedLanguages), len(results))
+	}
+
+	for i, r := range results {
+		if r.Name != employees[i].Name {
+			t.Errorf("expected employee name to be %s; got %s", employees[i].Name, r.Name)
+		}
+		if r.ProgrammingLanguage != expectedLanguages[i] {
+			t.Errorf("expected programming language of %s to be %q; got %q", r.Name, expectedLanguages[i], r.ProgrammingLanguage)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_Response_Without_Matching_Records(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id;col=id"`
+		Name string `paginate:"filter;col=name"`
+	}
+
+	u, err := url.Parse("http://localhost?name=Nobody")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scan is never called since there are no records.
+	if pag.NextData() {
+		t.Fatal("we should not have records for name=Nobody")
+	}
+
+	res := pag.Response()
+	if res.TotalSize != 0 || res.PageCount != 0 || res.TotalPages != 0 || res.HasNextPage || res.Partial {
+		t.Errorf("we should have an empty and complete response; got %+v", res)
+	}
+}
+
+func TestNewPaginatorPsql_Reset_And_Fetch_Two_Pages(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id;col=id"`
+		Name string `paginate:"col=name"`
+	}
+
+	u, err := url.Parse("http://localhost?page_size=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fetch returns the names of the employees of the current page.
+	fetch := func() []string {
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, employee.Name)
+		}
+		return names
+	}
+
+	expected := []string{"Ringo", "Bill", "Mark", "John", "Fred"}
+	if names := fetch(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have %v in the first page; got %v", expected, names)
+	}
+
+	pag.Reset()
+	if err = pag.SetPage(2); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []string{"Rob", "Juliana", "Erika", "Maria", "Rafael"}
+	if names := fetch(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have %v in the second page; got %v", expected, names)
+	}
+
+	res := pag.Response()
+	if res.PageNumber != 2 || res.PageCount != 5 || res.TotalSize != 10 || res.HasNextPage {
+		t.Errorf("we should be in the last page with 5 records; got %+v", res)
+	}
+}
+
+func TestNewPaginatorPsql_JSON_Filters(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id;col=id"`
+		Name string `paginate:"col=name"`
+		Meta string `paginate:"filter;json;col=meta"`
+	}
+
+	// fetch returns the names of the employees matching the given query.
+	fetch := func(query string) []string {
+		u, err := url.Parse("http://localhost?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, employee.Name)
+		}
+		return names
+	}
+
+	expected := []string{"Rob", "Juliana", "Erika", "Maria", "Rafael"}
+	if names := fetch("meta.role=manager"); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the managers %v; got %v", expected, names)
+	}
+
+	expected = []string{"John", "Fred"}
+	if names := fetch("meta.role=developer&meta.team.language=Python"); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the Python developers %v; got %v", expected, names)
+	}
+
+	if names := fetch("meta.role=admin"); len(names) != 0 {
+		t.Errorf("we should not have admins; got %v", names)
+	}
+}
+
+func TestNewPaginatorPsql_Array_Filters(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id;col=id"`
+		Name string `paginate:"col=name"`
+		Tags string `paginate:"filter;array;col=tags"`
+	}
+
+	// fetch returns the names of the employees matching the given query.
+	fetch := func(query string) []string {
+		u, err := url.Parse("http://localhost?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, employee.Name)
+		}
+		return names
+	}
+
+	expected := []string{"John", "Fred"}
+	if names := fetch("tags=python"); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the Python developers %v; got %v", expected, names)
+	}
+
+	expected = []string{"John", "Fred", "Rob", "Juliana", "Erika", "Maria", "Rafael"}
+	if names := fetch("tags=python&tags=manager"); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the Python developers and the managers %v; got %v", expected, names)
+	}
+
+	if names := fetch("tags=rust"); len(names) != 0 {
+		t.Errorf("we should not have Rust developers; got %v", names)
+	}
+}
+
+func TestNewPaginatorPsql_DefaultOrderBy(t *testing.T) {
+	type Employee struct {
+		ID           int    `paginate:"id;col=id"`
+		Name         string `paginate:"col=name"`
+		WorkerNumber int    `paginate:"col=worker_number"`
+	}
+
+	// fetch returns the names of the employees of the first page of the given query.
+	fetch := func(query string) []string {
+		u, err := url.Parse("http://localhost?page_size=3&" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), DefaultOrderBy("worker_number", "DESC"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, employee.Name)
+		}
+		return names
+	}
+
+	expected := []string{"Rafael", "Maria", "Erika"}
+	if names := fetch(""); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the last employees by default %v; got %v", expected, names)
+	}
+
+	expected = []string{"Bill", "Erika", "Fred"}
+	if names := fetch("sort=+name"); !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the employees sorted by name %v; got %v", expected, names)
+	}
+}
+
+func TestNewPaginatorPsql_TimeLayout_Date_Range(t *testing.T) {
+	type Employee struct {
+		ID         int       `paginate:"id;col=id"`
+		Name       string    `paginate:"col=name"`
+		DateJoined time.Time `paginate:"filter;col=date_joined"`
+	}
+
+	// count returns the number of employees matching the given query.
+	count := func(query string) int {
+		u, err := url.Parse("http://localhost?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), TimeLayout("2006-01-02"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		results := make([]Employee, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, employee)
+		}
+		return len(results)
+	}
+
+	// All the employees joined recently.
+	if n := count("date_joined=2000-01-01..2100-01-01"); n != 10 {
+		t.Errorf("we should have 10 records joined in this century; got %d", n)
+	}
+
+	if n := count("date_joined<2000-01-01"); n != 0 {
+		t.Errorf("we should have 0 records joined before 2000; got %d", n)
+	}
+}
+
+func TestNewPaginatorPsql_IN_Clause_With_Integer_Column(t *testing.T) {
+	type Employee struct {
+		ID           int    `paginate:"id;col=id"`
+		Name         string `paginate:"col=name"`
+		WorkerNumber int    `paginate:"filter;col=worker_number"`
+	}
+
+	u, err := url.Parse("http://localhost?worker_number=1&worker_number=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, employee.Name)
+	}
+
+	expected := []string{"Ringo", "Bill"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the workers 1 and 2 %v; got %v", expected, names)
+	}
+}
+
+func TestNewPaginatorPsql_Raw_Lateral_Join(t *testing.T) {
+	type Employee struct {
+		ID                  int    `paginate:"id;col=id"`
+		Name                string `paginate:"col=name"`
+		WorkerNumber        int    `paginate:"filter;col=worker_number"`
+		ProgrammingLanguage string `paginate:"col=lang.programming_language"`
+	}
+
+	u, err := url.Parse("http://localhost?worker_number>1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawJoin, err := NewRawJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawJoin.AddRaw("JOIN LATERAL (SELECT programming_language FROM developer " +
+		"WHERE developer.employee_id = employees.id AND programming_language = ?) AS lang ON true")
+	rawJoin.AddArg("Go")
+
+	err = pag.AddJoinClause(rawJoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sql, args, err := pag.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := psqlTestDB.Query(sql, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(pag.GetRowPtrArgs()...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if employee.ProgrammingLanguage != "Go" {
+			t.Errorf("we should only have Go developers; got %q", employee.ProgrammingLanguage)
+		}
+		names = append(names, employee.Name)
+	}
+
+	expected := []string{"Bill", "Mark"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("we should have the Go developers %v; got %v", expected, names)
+	}
+}
+
+func TestNewPaginatorPsql_Count(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id;col=id"`
+		Name     string `paginate:"col=name"`
+		LastName string `paginate:"filter;col=last_name"`
+	}
+
+	u, err := url.Parse("http://localhost?last_name=Smith&page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Count can be called before fetching the records.
+	count, err := pag.Count(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 5 {
+		t.Errorf("we should have 5 employees with the last name Smith; got %d", count)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if res := pag.Response(); res.TotalSize != count {
+		t.Errorf("the count %d should be the total size of the window function; got %d", count, res.TotalSize)
+	}
+}
+
+func TestNewPaginatorPsql_SoftDelete(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id;col=id"`
+		Name string `paginate:"col=name"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// All the employees have a date_joined, so if we use it as the soft
+	// delete column all of them should be considered as deleted.
+	tests := []struct {
+		opts     []Option
+		expected int
+	}{
+		{opts: []Option{TableName("employees"), SoftDelete("date_joined")}, expected: 0},
+		{opts: []Option{TableName("employees"), SoftDelete("date_joined"), IncludeDeleted()}, expected: 10},
+		{opts: []Option{TableName("employees"), SoftDelete("null_date")}, expected: 10},
+	}
+
+	for _, tt := range tests {
+		pag, err := NewPaginator(Employee{}, "postgres", *u, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = pag.Execute(context.Background(), psqlTestDB)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		count := 0
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count++
+		}
+
+		if count != tt.expected {
+			t.Errorf("we should have %d employees; got %d", tt.expected, count)
+		}
+	}
+}
+
+func TestNewPaginatorPsql_Scan_Bytes(t *testing.T) {
+	type Employee struct {
+		ID        int    `paginate:"id;col=id"`
+		NullBytes []byte `paginate:"col=null_bytes"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first 5 employees are developers with their programming language
+	// in the column null_bytes, the rest should be left nil.
+	languages := make([]string, 0)
+	nils := 0
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if employee.NullBytes == nil {
+			nils++
+			continue
+		}
+		languages = append(languages, string(employee.NullBytes))
+	}
+
+	expected := []string{"Go", "Go", "Go", "Python", "Python"}
+	if !reflect.DeepEqual(languages, expected) {
+		t.Errorf("expected the bytes to be %v; got %v", expected, languages)
+	}
+	if nils != 5 {
+		t.Errorf("we should have 5 employees without bytes; got %d", nils)
+	}
+}
+
+func TestNewPaginatorPsql_NullInt64_And_NullInt32(t *testing.T) {
+	type Employee struct {
+		ID           int       `paginate:"id;col=id"`
+		WorkerNumber NullInt64 `paginate:"col=worker_number"`
+		NullInt      NullInt32 `paginate:"col=null_int"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !employee.WorkerNumber.Valid {
+			t.Errorf("expected the worker number to be valid; got %+v", employee)
+		}
+		if employee.NullInt.Valid {
+			t.Errorf("expected the null int to be NULL; got %+v", employee.NullInt)
+		}
+		count++
+	}
+
+	if count != 10 {
+		t.Errorf("we should have 10 employees; got %d", count)
+	}
+}
+
+func TestNewPaginatorPsql_NullDecimal_And_NullFloat32(t *testing.T) {
+	type Employee struct {
+		ID          int         `paginate:"id;col=id"`
+		NullNumeric NullDecimal `paginate:"col=null_numeric"`
+		Salary      NullFloat32 `paginate:"col=salary"`
+	}
+
+	u, err := url.Parse("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first 5 employees are developers with a bonus of 12345678.91 in the
+	// column null_numeric, which should be scanned without losing precision.
+	decimals := make([]string, 0)
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !employee.Salary.Valid || employee.Salary.Float32 == 0 {
+			t.Errorf("expected the salary to be valid; got %+v", employee.Salary)
+		}
+		if employee.NullNumeric.Valid {
+			decimals = append(decimals, employee.NullNumeric.Decimal)
+		}
+	}
+
+	expected := []string{"12345678.91", "12345678.91", "12345678.91", "12345678.91", "12345678.91"}
+	if !reflect.DeepEqual(decimals, expected) {
+		t.Errorf("expected the decimals to be %v; got %v", expected, decimals)
+	}
+}
+
+func TestNewPaginatorPsql_QuoteIdentifiers(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id;col=id"`
+		Name  string `paginate:"col=name"`
+		Order int    `paginate:"filter;col=order"`
+	}
+
+	u, err := url.Parse("http://localhost?order=1&sort=-order")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), QuoteIdentifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first 5 employees are developers with the order 1.
+	count := 0
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if employee.Order != 1 {
+			t.Errorf("expected the order of the employee to be 1; got %d", employee.Order)
+		}
+		count++
+	}
+
+	if count != 5 {
+		t.Errorf("we should have 5 employees; got %d", count)
+	}
+}
+
+func TestNewPaginatorPsql_QuoteIdentifiers_Keyset_Search(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id;col=id"`
+		Name  string `paginate:"col=name"`
+		Order int    `paginate:"col=order"`
+	}
+
+	// fetch returns the names of the employees of the page after the given
+	// continuation token and the response of the paginator.
+	fetch := func(cursor string) ([]string, PaginationResponse) {
+		u, err := url.Parse("http://localhost?q=r&sort=-order&page_size=3&cursor=" + cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), QuoteIdentifiers(),
+			Search("q", "name"), KeysetPagination("cursor"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, args, err := pag.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := psqlTestDB.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			err = rows.Scan(pag.GetRowPtrArgs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, 0)
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, employee.Name)
+		}
+		return names, pag.Response()
+	}
+
+	// The developers have the order 1, so they come first.
+	expected := [][]string{
+		{"Ringo", "Mark", "Fred"},
+		{"Rob", "Erika", "Maria"},
+		{"Rafael"},
+	}
+
+	cursor := ""
+	for i, names := range expected {
+		results, res := fetch(cursor)
+		if fmt.Sprint(results) != fmt.Sprint(names) {
+			t.Errorf("page %d should have the employees %v; got %v", i+1, names, results)
+		}
+		cursor = res.NextCursor
+	}
+}
+
+func TestNewPaginatorPsql_NoSelect_Filter(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id;col=id"`
+		Name  string `paginate:"col=name"`
+		Order int    `paginate:"filter;noselect;col=order"`
+	}
+
+	u, err := url.Parse("http://localhost?order=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), QuoteIdentifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pag.Execute(context.Background(), psqlTestDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first 5 employees are developers with the order 1, but
+	// the order is not selected, so it is left with its zero value.
+	count := 0
+	for pag.NextData() {
+		employee := Employee{}
+		err = pag.Scan(&employee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if employee.Order != 0 {
+			t.Errorf("expected the order of the employee not to be selected; got %d", employee.Order)
+		}
+		count++
+	}
+
+	if count != 5 {
+		t.Errorf("we should have 5 employees; got %d", count)
+	}
+}
+
+func TestNewPaginatorPsql_Bool_Filter(t *testing.T) {
+	type Employee struct {
+		ID       int      `paginate:"id;col=id"`
+		Name     string   `paginate:"col=name"`
+		NullBool NullBool `paginate:"filter;col=null_bool"`
+	}
+
+	// Only Bill and Fred have the value true in the null_bool column.
+	for value, expected := range map[string]int{"true": 2, "1": 2, "false": 0, "0": 0} {
+		u, err := url.Parse("http://localhost?null_bool=" + value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pag, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = pag.Execute(context.Background(), psqlTestDB)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		count := 0
+		for pag.NextData() {
+			employee := Employee{}
+			err = pag.Scan(&employee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !employee.NullBool.Valid || !employee.NullBool.Bool {
+				t.Errorf("expected the null_bool of the employee to be true; got %+v", employee.NullBool)
+			}
+			count++
+		}
+
+		if count != expected {
+			t.Errorf("we should have %d employees with null_bool=%s; got %d", expected, value, count)
+		}
+	}
+}
@@ -1,10 +1,21 @@
 package paginate
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func ExampleNewPaginator_1() {
@@ -258,12 +269,12 @@ func TestCreateWhereClauseMultipleFilters(t *testing.T) {
 	param6 := parameter{"cars", "<=", "5"}
 	params := parameters{param1, param2, param3, param4, param5, param6}
 	c := make(chan whereClause)
-	go createWhereClause("postgres", colNames, params, []RawWhereClause{}, c)
+	go createWhereClause("postgres", "", colNames, params, nil, nil, _and, []RawWhereClause{}, false, c)
 	where := <-c
 	if !where.exists {
 		t.Errorf("where clauses should exists; got %v", where.exists)
 	}
-	expectedCLAUSE := " WHERE age > $%v AND skills <> $%v AND cars >= $%v AND cars > $%v AND cars < $%v AND cars <= $%v"
+	expectedCLAUSE := " WHERE age > ? AND skills <> ? AND cars >= ? AND cars > ? AND cars < ? AND cars <= ?"
 	if where.clause != expectedCLAUSE {
 		t.Errorf("filter clause should be %v; got %v", expectedCLAUSE, where.clause)
 	}
@@ -275,62 +286,6222 @@ func TestCreateWhereClauseMultipleFilters(t *testing.T) {
 	}
 }
 
+func TestCreateWhereClause_Empty_IN_And_NOT_IN(t *testing.T) {
+	colNames := []string{"name", "age"}
+	params := parameters{{"name", _in, ""}, {"age", ">", "33"}}
+	c := make(chan whereClause)
+	go createWhereClause("postgres", "", colNames, params, nil, nil, _and, []RawWhereClause{}, false, c)
+	where := <-c
+	expectedCLAUSE := " WHERE 1=0 AND age > ?"
+	if where.clause != expectedCLAUSE {
+		t.Errorf("filter clause should be %v; got %v", expectedCLAUSE, where.clause)
+	}
+	if len(where.args) != 1 || where.args[0] != "33" {
+		t.Errorf("where clause args should be [33]; got %v", where.args)
+	}
+	if !where.matchesNothing {
+		t.Errorf("expected an empty IN clause ANDed with the rest to match nothing")
+	}
+
+	go createWhereClause("postgres", "", colNames, params, nil, nil, _or, []RawWhereClause{}, false, c)
+	where = <-c
+	if where.matchesNothing {
+		t.Errorf("expected an empty IN clause ORed with the rest to match records")
+	}
+
+	params = parameters{{"name", _notin, ""}}
+	go createWhereClause("mysql", "", colNames, params, nil, nil, _and, []RawWhereClause{}, false, c)
+	where = <-c
+	expectedCLAUSE = " WHERE 1=1"
+	if where.clause != expectedCLAUSE {
+		t.Errorf("filter clause should be %v; got %v", expectedCLAUSE, where.clause)
+	}
+	if where.matchesNothing {
+		t.Errorf("expected an empty NOT IN clause to match records")
+	}
+	if len(where.args) != 0 {
+		t.Errorf("where clause should not have args; got %v", where.args)
+	}
+}
+
+func TestNewPaginator_Invalid_Column_Names(t *testing.T) {
+	u, err := url.Parse("http://ottotech.com?name=rob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Injected struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter;col=name = name OR 1=1 --"`
+	}
+	if _, err = NewPaginator(Injected{}, "postgres", *u); err == nil {
+		t.Error("expected an error with a malicious column name")
+	}
+
+	type Dropped struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"col=name, (SELECT 1); DROP TABLE employee"`
+	}
+	if _, err = NewPaginator(Dropped{}, "mysql", *u); err == nil {
+		t.Error("expected an error with a malicious column name")
+	}
+
+	type Qualified struct {
+		ID       int    `paginate:"id;col=employee.id"`
+		Language string `paginate:"filter;col=developer.programming_language"`
+	}
+	if _, err = NewPaginator(Qualified{}, "postgres", *u); err != nil {
+		t.Errorf("expected qualified column names to be valid; got %v", err)
+	}
+}
+
+func TestPaginate_IN_Clause_With_Numeric_Columns(t *testing.T) {
+	type Employee struct {
+		ID           int     `paginate:"id;filter"`
+		WorkerNumber int     `paginate:"filter"`
+		Salary       float64 `paginate:"filter"`
+		Age          uint8   `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?worker_number=1&worker_number=2&salary<>4650.9&salary<>7550&age=30&age=x&id=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dialect := range []string{"postgres", "mysql"} {
+		paginator, err := NewPaginator(Employee{}, dialect, *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Values that are not numbers and values of other clauses are left as they are.
+		expected := []interface{}{"3", int64(1), int64(2), float64(4650.9), float64(7550), uint64(30), "x"}
+		if !reflect.DeepEqual(args, expected) {
+			t.Errorf("expected %s args to be %#v; got %#v", dialect, expected, args)
+		}
+	}
+}
+
+func TestPaginate_Between_Filter(t *testing.T) {
+	type Employee struct {
+		ID     int     `paginate:"id"`
+		Name   string  `paginate:"filter"`
+		Salary float64 `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?salary=4000..8000&name=rob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, salary, count(*) over() FROM employee WHERE name = $1 AND salary BETWEEN $2 AND $3 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	rightARGS := []interface{}{"rob", "4000", "8000"}
+	if len(args) != len(rightARGS) {
+		t.Fatalf("expected args to be %v; got %v", rightARGS, args)
+	}
+	for i := range args {
+		if args[i] != rightARGS[i] {
+			t.Errorf("arg number %v should be %v; got %v", i, rightARGS[i], args[i])
+		}
+	}
+
+	// Malformed ranges should be skipped.
+	for _, rawURL := range []string{
+		"http://ottotech.com?salary=4000..",
+		"http://ottotech.com?salary=..8000",
+		"http://ottotech.com?salary=1..2..3",
+	} {
+		u, err = url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err = NewPaginator(Employee{}, "mysql", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err = paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = "SELECT id, name, salary, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0"
+		if cmd != expected || len(args) != 0 {
+			t.Errorf("expected the malformed range %q to be skipped; got %q %v", rawURL, cmd, args)
+		}
+	}
+
+	// Ranges are only used for numeric and time columns, so the values
+	// of the string columns are kept as they are.
+	u, err = url.Parse("http://ottotech.com?name=a..b@x.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, salary, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected || fmt.Sprint(args) != "[a..b@x.com]" {
+		t.Errorf("expected sql command to be %q with the args [a..b@x.com]; got %q %v", expected, cmd, args)
+	}
+}
+
+func TestPaginate_Contains_Filter(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string
+	}
+	u, err := url.Parse("http://ottotech.com?name=~50%25_off&last_name=~smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// last_name is not tagged with filter, so it should not be filtered.
+	expected := "SELECT id, name, last_name, count(*) over() FROM employee WHERE name ILIKE $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 1 || args[0] != `%50\%\_off%` {
+		t.Errorf("expected args to be [%%50\\%%\\_off%%]; got %v", args)
+	}
+
+	paginator, err = NewPaginator(Employee{}, "mysql", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, last_name, count(*) over() FROM employee WHERE name LIKE ? ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestPaginate_Not_Contains_Filter(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string
+	}
+	u, err := url.Parse("http://ottotech.com?name=!~temp_&name<>rob&name<>ringo&last_name=!~smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// last_name is not tagged with filter, so it should not be filtered.
+	expected := "SELECT id, name, last_name, count(*) over() FROM employee WHERE name NOT ILIKE $1 AND name NOT IN($2,$3) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if !reflect.DeepEqual(args, []interface{}{`%temp\_%`, "rob", "ringo"}) {
+		t.Errorf("expected args to be [%%temp\\_%% rob ringo]; got %v", args)
+	}
+
+	paginator, err = NewPaginator(Employee{}, "mysql", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, last_name, count(*) over() FROM employee WHERE name NOT LIKE ? AND name NOT IN(?,?) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestPaginate_IS_NULL_And_IS_NOT_NULL_Filters(t *testing.T) {
+	type Employee struct {
+		ID         int      `paginate:"id"`
+		Name       string   `paginate:"filter"`
+		NullDate   NullTime `paginate:"filter"`
+		NullString NullString
+	}
+	u, err := url.Parse("http://ottotech.com?null_date=null&null_date=notnull&name=rob&name=ringo&null_string=null")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The null values should not be grouped in an IN clause and null_string
+	// should not be filtered since it is not tagged with filter.
+	expected := "SELECT id, name, null_date, null_string, count(*) over() FROM employee WHERE name IN($1,$2) AND null_date IS NULL AND null_date IS NOT NULL ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 2 || args[0] != "rob" || args[1] != "ringo" {
+		t.Errorf("expected args to be [rob ringo]; got %v", args)
+	}
+}
+
+func TestPaginate_Quoted_Null_Filter_Values(t *testing.T) {
+	type Employee struct {
+		ID       int        `paginate:"id"`
+		Name     NullString `paginate:"filter"`
+		LastName string     `paginate:"filter"`
+	}
+	u, err := url.Parse(`http://ottotech.com?name=null&last_name="null"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The unquoted null should match the NULL values while the quoted one
+	// should match the literal string.
+	expected := "SELECT id, name, last_name, count(*) over() FROM employee WHERE name IS NULL AND last_name = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[null]" {
+		t.Errorf("expected args to be [null]; got %v", args)
+	}
+
+	u, err = url.Parse(`http://ottotech.com?name="notnull"&name=%22null%22`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, last_name, count(*) over() FROM employee WHERE name IN($1,$2) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[notnull null]" {
+		t.Errorf("expected args to be [notnull null]; got %v", args)
+	}
+
+	// A column that is not nullable should match the literal strings even
+	// when they are not quoted.
+	u, err = url.Parse(`http://ottotech.com?last_name=null`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, last_name, count(*) over() FROM employee WHERE last_name = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[null]" {
+		t.Errorf("expected args to be [null]; got %v", args)
+	}
+}
+
+func TestPaginate_Empty_Filter_Values(t *testing.T) {
+	type Employee struct {
+		ID       int      `paginate:"id"`
+		Name     string   `paginate:"filter"`
+		NullDate NullTime `paginate:"filter;param=joined"`
+	}
+	u, err := url.Parse("http://ottotech.com?joined=&joined=&name=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// By default the parameters with empty values should be ignored.
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, null_date, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 0 {
+		t.Errorf("expected no args; got %v", args)
+	}
+
+	// With EmptyAsNull the parameters with empty values should be
+	// interpreted as IS NULL clauses without arguments.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, EmptyAsNull())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, null_date, count(*) over() FROM employee WHERE name IS NULL AND null_date IS NULL ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 0 {
+		t.Errorf("expected no args; got %v", args)
+	}
+}
+
+func TestPaginate_SoftDelete(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// By default the soft deleted records should be filtered out.
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, SoftDelete("deleted_at"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM employee WHERE name = $1 AND (deleted_at IS NULL) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 1 || args[0] != "rob" {
+		t.Errorf("expected args to be [rob]; got %v", args)
+	}
+
+	// With IncludeDeleted the soft deleted records should be paginated too.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, SoftDelete("deleted_at"), IncludeDeleted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// A raw where clause with OR should not match the soft deleted records.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, SoftDelete("deleted_at"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := NewRawWhereClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.AddPredicate("name = ? OR name = ?")
+	raw.AddArg("ringo")
+	raw.AddArg("john")
+	if err = paginator.AddWhereClause(raw); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, count(*) over() FROM employee WHERE name = $1 AND (deleted_at IS NULL) AND (name = $2 OR name = $3) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// With join clauses and QuoteIdentifiers the column should be
+	// qualified and quoted like the rest of the columns.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, SoftDelete("deleted_at"), QuoteIdentifiers(), TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	join, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "manager", "employee_id")
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `SELECT "employees"."id", "employees"."name", count(*) over() FROM "employees" JOIN "manager" ON "employees"."id" = "manager"."employee_id" WHERE "employees"."name" = $1 AND ("employees"."deleted_at" IS NULL) ORDER BY "id" LIMIT 30 OFFSET 0`
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	for _, column := range []string{"", "deleted_at IS NULL OR 1=1"} {
+		if _, err = NewPaginator(Employee{}, "postgres", *u, SoftDelete(column)); err == nil {
+			t.Errorf("expected an error with the soft delete column %q", column)
+		}
+	}
+}
+
+func TestNewPaginator_StrictPaging(t *testing.T) {
+	type Person struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+
+	for _, page := range []string{"-3", "0", "abc"} {
+		u, err := url.Parse("http://ottotech.com?page=" + page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = NewPaginator(Person{}, "postgres", *u, StrictPaging()); err == nil {
+			t.Errorf("expected an error with page %q in strict mode", page)
+		}
+
+		// By default the page should be clamped to the first page.
+		paginator, err := NewPaginator(Person{}, "postgres", *u)
+		if err != nil {
+			t.Fatalf("expected no error with page %q by default; got %v", page, err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "SELECT id, name, count(*) over() FROM person ORDER BY id LIMIT 30 OFFSET 0"
+		if cmd != expected {
+			t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+		}
+	}
+
+	for _, rawURL := range []string{"http://ottotech.com?page=2", "http://ottotech.com"} {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = NewPaginator(Person{}, "postgres", *u, StrictPaging()); err != nil {
+			t.Errorf("expected no error with url %q in strict mode; got %v", rawURL, err)
+		}
+	}
+}
+
+func TestRawWhereClause_Builder_Helpers(t *testing.T) {
+	cases := []struct {
+		dialect  string
+		build    func(raw *RawWhereClause)
+		expected string
+		args     []interface{}
+	}{
+		{"postgres", func(raw *RawWhereClause) { raw.Eq("name", "ringo") }, "name = $1", []interface{}{"ringo"}},
+		{"mysql", func(raw *RawWhereClause) { raw.Eq("age", 33) }, "age = ?", []interface{}{33}},
+		{"postgres", func(raw *RawWhereClause) { raw.Like("name", "50%_off") }, "name ILIKE $1", []interface{}{`%50\%\_off%`}},
+		{"mysql", func(raw *RawWhereClause) { raw.Like("name", "rin") }, "name LIKE ?", []interface{}{"%rin%"}},
+		{"mysql", func(raw *RawWhereClause) { raw.Between("salary", 4000, 8000) }, "salary BETWEEN ? AND ?", []interface{}{4000, 8000}},
+		{
+			"postgres",
+			func(raw *RawWhereClause) {
+				raw.Eq("name", "ringo").Between("salary", 4000, 8000).Like("last_name", "st")
+			},
+			"name = $1 AND salary BETWEEN $2 AND $3 AND last_name ILIKE $4",
+			[]interface{}{"ringo", 4000, 8000, "%st%"},
+		},
+		{
+			"postgres",
+			func(raw *RawWhereClause) {
+				raw.AddPredicate("name = ? OR name = ?")
+				raw.AddArg("ringo")
+				raw.AddArg("rob")
+				raw.Eq("tenant_id", 1)
+			},
+			"(name = $1 OR name = $2) AND tenant_id = $3",
+			[]interface{}{"ringo", "rob", 1},
+		},
+	}
+
+	for _, c := range cases {
+		raw, err := NewRawWhereClause(c.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.build(&raw)
+		if raw.String() != c.expected {
+			t.Errorf("expected predicate to be %q; got %q", c.expected, raw.String())
+		}
+		if fmt.Sprint(raw.args) != fmt.Sprint(c.args) {
+			t.Errorf("expected args to be %v; got %v", c.args, raw.args)
+		}
+	}
+
+	// Invalid column names should be rejected by AddWhereClause.
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type Employee struct {
+		ID int `paginate:"id"`
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, column := range []string{"name = name OR 1", "name; DROP", ""} {
+		raw, err := NewRawWhereClause("postgres")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = paginator.AddWhereClause(*raw.Eq(column, 1)); err == nil {
+			t.Errorf("expected an error with the invalid column name %q", column)
+		}
+	}
+}
+
+func TestRawWhereClause_Builder_Helpers_With_Paginator(t *testing.T) {
+	type Employee struct {
+		ID     int     `paginate:"id"`
+		Name   string  `paginate:"filter"`
+		Salary float64 `paginate:"col=salary"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := NewRawWhereClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.AddWhereClause(*raw.Between("salary", 4000, 8000)); err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, salary, count(*) over() FROM employee WHERE name = $1 AND (salary BETWEEN $2 AND $3) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[rob 4000 8000]" {
+		t.Errorf("expected args to be [rob 4000 8000]; got %v", args)
+	}
+}
+
+func TestPaginator_AddWhereClause_StrictRawClauses(t *testing.T) {
+	type Employee struct {
+		ID       int       `paginate:"id"`
+		Name     string    `paginate:"filter"`
+		Salary   float64   `paginate:"col=salary"`
+		TenantID int       `paginate:"col=tenant_id"`
+		Joined   time.Time `paginate:"col=date_joined"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		predicate string
+		args      []interface{}
+		valid     bool
+	}{
+		{"name = ? AND salary > ?", []interface{}{"ringo", 4000}, true},
+		{"employee.tenant_id = ? OR name IS NOT NULL", []interface{}{1}, true},
+		{"LOWER(name) LIKE 'rin%' AND name NOT IN ('x', 'y''s')", nil, true},
+		{"date_joined::date > ? AND CAST(salary AS int) BETWEEN 1 AND 2", []interface{}{"2020-01-01"}, true},
+		{"manager.name = ?", []interface{}{"bill"}, true},
+		{"password_hash = ?", []interface{}{"x"}, false},
+		{"name = ? OR 1=1 OR tenant = tenant_id", []interface{}{"x"}, false},
+		{"employee.password_hash IS NULL", nil, false},
+		{"other.tenant_id = ?", []interface{}{2}, false},
+		{"id IN (SELECT employee_id FROM other)", nil, false},
+		{"name = ? /* ' */ OR password_hash IS NULL /* ' */", []interface{}{"x"}, false},
+		{"name = ? -- ' OR password_hash IS NULL", []interface{}{"x"}, false},
+		{"name = ? # ' OR password_hash IS NULL", []interface{}{"x"}, false},
+		{`name = 'a\'' OR password_hash IS NULL OR name = ''`, nil, false},
+	}
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, StrictRawClauses("manager"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := NewRawWhereClause("postgres")
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw.AddPredicate(tt.predicate)
+		for _, arg := range tt.args {
+			raw.AddArg(arg)
+		}
+		err = paginator.AddWhereClause(raw)
+		if tt.valid && err != nil {
+			t.Errorf("expected %q to be accepted; got %v", tt.predicate, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("expected %q to be rejected", tt.predicate)
+		}
+	}
+
+	// Without the option any predicate should be accepted.
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := NewRawWhereClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.AddPredicate("password_hash IS NULL")
+	if err = paginator.AddWhereClause(raw); err != nil {
+		t.Errorf("expected no error without StrictRawClauses; got %v", err)
+	}
+
+	// An accepted predicate with OR cannot bypass the other where clauses.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, StrictRawClauses())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, predicate := range []string{"tenant_id = ?", "name = ? OR name IS NOT NULL"} {
+		raw, err = NewRawWhereClause("postgres")
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw.AddPredicate(predicate)
+		raw.AddArg(1)
+		if err = paginator.AddWhereClause(raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, salary, tenant_id, date_joined, count(*) over() FROM employee " +
+		"WHERE (tenant_id = $1) AND (name = $2 OR name IS NOT NULL) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	if _, err = NewPaginator(Employee{}, "postgres", *u, StrictRawClauses("manager; DROP")); err == nil {
+		t.Error("expected an error with an invalid table name")
+	}
+}
+
+func TestRenderPlaceholders(t *testing.T) {
+	sqlStr := "name = ? AND salary BETWEEN ? AND ?"
+	tests := []struct {
+		style    string
+		expected string
+	}{
+		{"?", "name = ? AND salary BETWEEN ? AND ?"},
+		{"$", "name = $1 AND salary BETWEEN $2 AND $3"},
+		{"@p", "name = @p1 AND salary BETWEEN @p2 AND @p3"},
+		{":", "name = :1 AND salary BETWEEN :2 AND :3"},
+	}
+	for _, tt := range tests {
+		if got := renderPlaceholders(sqlStr, "postgres", placeholderStyles[tt.style]); got != tt.expected {
+			t.Errorf("style %q: expected %q; got %q", tt.style, tt.expected, got)
+		}
+	}
+
+	// The question marks inside string literals are not placeholders.
+	sqlStr = "note <> 'why?' AND name = ? AND title = 'it''s ?' AND age > ?"
+	expected := "note <> 'why?' AND name = $1 AND title = 'it''s ?' AND age > $2"
+	if got := renderPlaceholders(sqlStr, "postgres", placeholderStyles["$"]); got != expected {
+		t.Errorf("expected %q; got %q", expected, got)
+	}
+	if n := countPlaceholders(sqlStr, "postgres"); n != 2 {
+		t.Errorf("expected 2 placeholders; got %d", n)
+	}
+
+	// The question marks inside comments are not placeholders either.
+	sqlStr = "name = ? /* why? */ AND age > ? -- or ?\nAND salary > ?"
+	expected = "name = $1 /* why? */ AND age > $2 -- or ?\nAND salary > $3"
+	if got := renderPlaceholders(sqlStr, "postgres", placeholderStyles["$"]); got != expected {
+		t.Errorf("expected %q; got %q", expected, got)
+	}
+
+	// Mysql and mariadb escape the quotes with backslashes and have # comments,
+	// while "--" should be followed by a space to start a comment.
+	mysqlTests := []struct {
+		sql   string
+		count int
+	}{
+		{`name = 'it\'s ?' AND age > ?`, 1},
+		{"name = ? # why?\nAND age > ?", 2},
+		{"age > 5--? AND name = ?", 2},
+		{"age > 5 -- ?\nAND name = ?", 1},
+	}
+	for _, tt := range mysqlTests {
+		if n := countPlaceholders(tt.sql, "mysql"); n != tt.count {
+			t.Errorf("expected %d placeholders in %q; got %d", tt.count, tt.sql, n)
+		}
+	}
+}
+
+func TestPaginate_Sequential_Placeholders(t *testing.T) {
+	type Employee struct {
+		ID         int    `paginate:"id"`
+		Name       string `paginate:"filter"`
+		Department string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo&department=sales&department=it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"),
+		GroupBy("id", "name", "department"), OrderByCase("department", "it", "sales"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	join, err := NewRawJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.AddRaw("LEFT JOIN developer ON developer.employee_id = employees.id AND developer.programming_language = ?")
+	join.AddArg("Go")
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+
+	where, err := NewRawWhereClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	where.AddPredicate("employees.notes <> 'why?' AND employees.tenant_id = ?")
+	where.AddArg(7)
+	if err = paginator.AddWhereClause(where); err != nil {
+		t.Fatal(err)
+	}
+
+	having, err := NewRawHavingClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	having.AddPredicate("count(developer.employee_id) > ?")
+	having.AddArg(0)
+	if err = paginator.AddHavingClause(having); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The placeholders of the join, where, having and "ORDER BY" clauses are
+	// numbered in the same order as their arguments.
+	expected := "SELECT employees.id, employees.name, employees.department, count(*) over() FROM employees " +
+		"LEFT JOIN developer ON developer.employee_id = employees.id AND developer.programming_language = $1 " +
+		"WHERE employees.name = $2 AND employees.department IN($3,$4) AND (employees.notes <> 'why?' AND employees.tenant_id = $5) " +
+		"GROUP BY employees.id, employees.name, employees.department HAVING count(developer.employee_id) > $6 " +
+		"ORDER BY CASE WHEN employees.department = $7 THEN 0 WHEN employees.department = $8 THEN 1 ELSE 2 END ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[Go Ringo sales it 7 0 it sales]" {
+		t.Errorf("expected args to be [Go Ringo sales it 7 0 it sales]; got %v", args)
+	}
+}
+
+func TestPaginate_PlaceholderStyle(t *testing.T) {
+	type Employee struct {
+		ID     int     `paginate:"id"`
+		Name   string  `paginate:"filter"`
+		Salary float64 `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&salary=4000..8000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dialect  string
+		style    string
+		expected string
+	}{
+		{"postgres", "@p", "SELECT id, name, salary, count(*) over() FROM employee WHERE name = @p1 AND salary BETWEEN @p2 AND @p3 ORDER BY id LIMIT 30 OFFSET 0"},
+		{"postgres", ":", "SELECT id, name, salary, count(*) over() FROM employee WHERE name = :1 AND salary BETWEEN :2 AND :3 ORDER BY id LIMIT 30 OFFSET 0"},
+		{"postgres", "?", "SELECT id, name, salary, count(*) over() FROM employee WHERE name = ? AND salary BETWEEN ? AND ? ORDER BY id LIMIT 30 OFFSET 0"},
+		{"mysql", "$", "SELECT id, name, salary, count(*) over() FROM employee WHERE name = $1 AND salary BETWEEN $2 AND $3 ORDER BY id LIMIT 30 OFFSET 0"},
+	}
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u, PlaceholderStyle(tt.style))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command to be %q; got %q", tt.expected, cmd)
+		}
+		if len(args) != 3 {
+			t.Errorf("expected 3 args; got %v", args)
+		}
+	}
+
+	if _, err = NewPaginator(Employee{}, "postgres", *u, PlaceholderStyle("%s")); err == nil {
+		t.Error("expected an error with an invalid placeholder style")
+	}
+}
+
+func TestPaginate_QuoteIdentifiers(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id"`
+		Name  string `paginate:"filter"`
+		Order int    `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?order=2&name=rob&sort=-order")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{"postgres", `SELECT "id", "name", "order", count(*) over() FROM "employee" WHERE "name" = $1 AND "order" = $2 ORDER BY "order" DESC,"id" LIMIT 30 OFFSET 0`},
+		{"mysql", "SELECT `id`, `name`, `order`, count(*) over() FROM `employee` WHERE `name` = ? AND `order` = ? ORDER BY `order` DESC,`id` LIMIT 30 OFFSET 0"},
+	}
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u, QuoteIdentifiers())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command to be %q; got %q", tt.expected, cmd)
+		}
+		if len(args) != 2 {
+			t.Errorf("expected 2 args; got %v", args)
+		}
+	}
+}
+
+func TestPaginate_QuoteIdentifiers_Keyset_Search(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id"`
+		Name  string `paginate:"filter"`
+		Order int    `paginate:"filter"`
+	}
+	cursor := encodeCursor([]interface{}{1, 3})
+	u, err := url.Parse("http://ottotech.com?q=rob&sort=-order&cursor=" + cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{"postgres", `SELECT "id", "name", "order", count(*) over() FROM "employee" WHERE ("name" ILIKE ANY(ARRAY[$1])) AND ("order" < $2 OR ("order" = $3 AND "id" > $4)) ORDER BY "order" DESC,"id" LIMIT 30 OFFSET 0`},
+		{"mysql", "SELECT `id`, `name`, `order`, count(*) over() FROM `employee` WHERE (`name` LIKE ?) AND (`order` < ? OR (`order` = ? AND `id` > ?)) ORDER BY `order` DESC,`id` LIMIT 30 OFFSET 0"},
+	}
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u, QuoteIdentifiers(), Search("q", "name"), KeysetPagination("cursor"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command to be %q; got %q", tt.expected, cmd)
+		}
+	}
+}
+
+func TestPaginate_QuoteIdentifiers_Join(t *testing.T) {
+	type Group struct {
+		ID    int `paginate:"id"`
+		Order int
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{"postgres", `SELECT "group"."id", "group"."order", count(*) over() FROM "group" JOIN "user" ON "group"."order" = "user"."id" ORDER BY "id" LIMIT 30 OFFSET 0`},
+		{"mysql", "SELECT `group`.`id`, `group`.`order`, count(*) over() FROM `group` JOIN `user` ON `group`.`order` = `user`.`id` ORDER BY `id` LIMIT 30 OFFSET 0"},
+	}
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Group{}, tt.dialect, *u, QuoteIdentifiers(), TableName("group"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		join, err := NewInnerJoinClause(tt.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		join.On("order", "user", "id")
+		if err = paginator.AddJoinClause(join); err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command to be %q; got %q", tt.expected, cmd)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		dialect    string
+		identifier string
+		expected   string
+	}{
+		{"postgres", "order", `"order"`},
+		{"postgres", "employees.order", `"employees"."order"`},
+		{"postgres", `my"col`, `"my""col"`},
+		{"mysql", "employees.order", "`employees`.`order`"},
+		{"mariadb", "my`col", "`my``col`"},
+	}
+	for _, tt := range tests {
+		if got := quoteIdentifier(tt.dialect, tt.identifier); got != tt.expected {
+			t.Errorf("expected %s to be quoted as %s for %s; got %s", tt.identifier, tt.expected, tt.dialect, got)
+		}
+	}
+}
+
+func TestConditionBuilder_Nested_Conditions(t *testing.T) {
+	type Employee struct {
+		ID       int     `paginate:"id"`
+		Name     string  `paginate:"filter"`
+		Salary   float64 `paginate:"col=salary"`
+		TenantID int     `paginate:"col=tenant_id"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{
+			dialect: "postgres",
+			expected: "SELECT id, name, salary, tenant_id, count(*) over() FROM employee WHERE name = $1 AND " +
+				"(tenant_id IN($2,$3) OR (salary > $4 AND salary <= $5 AND name <> $6)) ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			dialect: "mysql",
+			expected: "SELECT id, name, salary, tenant_id, count(*) over() FROM employee WHERE name = ? AND " +
+				"(tenant_id IN(?,?) OR (salary > ? AND salary <= ? AND name <> ?)) ORDER BY id LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cb, err := NewConditionBuilder(tt.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := cb.Where(cb.Or(
+			cb.In("tenant_id", 1, 2),
+			cb.And(cb.Gt("salary", 4000), cb.Lte("salary", 8000), cb.Ne("name", "ringo")),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = paginator.AddWhereClause(raw); err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command to be %q; got %q", tt.expected, cmd)
+		}
+		expectedArgs := []interface{}{"rob", 1, 2, 4000, 8000, "ringo"}
+		if !reflect.DeepEqual(args, expectedArgs) {
+			t.Errorf("expected args to be %v; got %v", expectedArgs, args)
+		}
+	}
+}
+
+func TestConditionBuilder_Invalid_Conditions(t *testing.T) {
+	cb, err := NewConditionBuilder("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []Condition{
+		cb.Eq("name = 1 OR 1", 1),
+		cb.In("tenant_id"),
+		cb.And(),
+		cb.Or(cb.Eq("name", "ringo"), cb.Gte("salary; DROP TABLE employee", 1)),
+		cb.And(cb.Lt("salary", 1), Condition{}),
+		{},
+	}
+	for i, c := range invalid {
+		if _, err = cb.Where(c); err == nil {
+			t.Errorf("expected an error with the invalid condition %d", i)
+		}
+	}
+
+	if _, err = NewConditionBuilder("sqlite"); err == nil {
+		t.Error("expected an error with an unsupported dialect")
+	}
+}
+
+func TestPaginator_SetFilterConjunction(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string `paginate:"filter"`
+		TenantID int    `paginate:"col=tenant_id"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&last_name=gates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.SetFilterConjunction("xor"); err == nil {
+		t.Error("expected an error with an invalid conjunction")
+	}
+	if err = paginator.SetFilterConjunction("or"); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := NewRawWhereClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.AddWhereClause(*raw.Eq("tenant_id", 1)); err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, last_name, tenant_id, count(*) over() FROM employee WHERE (name = $1 OR last_name = $2) AND (tenant_id = $3) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[rob gates 1]" {
+		t.Errorf("expected args to be [rob gates 1]; got %v", args)
+	}
+
+	if err = paginator.SetFilterConjunction("AND"); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, last_name, tenant_id, count(*) over() FROM employee WHERE name = $1 AND last_name = $2 AND (tenant_id = $3) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestNewPaginator_Custom_Page_Parameter_Names(t *testing.T) {
+	type Person struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&p=3&per_page=5&page=9&page_size=50")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Person{}, "postgres", *u, PageParam("p"), PageSizeParam("per_page"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM person WHERE name = $1 ORDER BY id LIMIT 5 OFFSET 10"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// The default parameter names should still work without the options.
+	paginator, err = NewPaginator(Person{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, count(*) over() FROM person WHERE name = $1 ORDER BY id LIMIT 50 OFFSET 400"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	u, err = url.Parse("http://ottotech.com?p=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewPaginator(Person{}, "postgres", *u, PageParam("p"), StrictPaging()); err == nil {
+		t.Error("expected an error with p=0 in strict mode")
+	}
+
+	if _, err = NewPaginator(Person{}, "postgres", *u, PageParam(" ")); err == nil {
+		t.Error("expected an error with an empty page parameter name")
+	}
+}
+
+func TestPaginate_Filter_With_Function(t *testing.T) {
+	type Employee struct {
+		ID         int       `paginate:"id"`
+		Name       string    `paginate:"filter;fn=LOWER"`
+		DateJoined time.Time `paginate:"filter;fn=DATE"`
+	}
+	u, err := url.Parse("http://ottotech.com?date_joined=2023-01-02&name=rob&name=ringo&sort=+date_joined")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, date_joined, count(*) over() FROM employee WHERE LOWER(name) IN($1,$2) AND DATE(date_joined) = $3 ORDER BY date_joined ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[rob ringo 2023-01-02]" {
+		t.Errorf("expected args to be [rob ringo 2023-01-02]; got %v", args)
+	}
+
+	type Invalid struct {
+		ID         int       `paginate:"id"`
+		DateJoined time.Time `paginate:"filter;fn=DATE(date_joined) OR 1=1 --"`
+	}
+	if _, err = NewPaginator(Invalid{}, "postgres", *u); err == nil {
+		t.Error("expected an error with an invalid function name")
+	}
+}
+
+func TestGetTotalPages(t *testing.T) {
+	tests := []struct {
+		totalSize, pageSize, expected int
+	}{
+		{totalSize: 30, pageSize: 10, expected: 3},
+		{totalSize: 25, pageSize: 10, expected: 3},
+		{totalSize: 5, pageSize: 10, expected: 1},
+		{totalSize: 0, pageSize: 10, expected: 0},
+		{totalSize: 25, pageSize: 0, expected: 0},
+	}
+	for _, tt := range tests {
+		if got := getTotalPages(tt.totalSize, tt.pageSize); got != tt.expected {
+			t.Errorf("expected %d total pages for %d records of page size %d; got %d", tt.expected, tt.totalSize, tt.pageSize, got)
+		}
+	}
+}
+
 func TestCreatePaginationClause_with_page_gt_1(t *testing.T) {
 	pageNumber := 2
 	pageSize := 30
 	c := make(chan string)
-	go createPaginationClause(pageNumber, pageSize, c)
-	clause := <-c
-	expectedCLAUSE := " LIMIT 30 OFFSET 30"
-	if clause != expectedCLAUSE {
-		t.Errorf("expected clause should be %v; got %v", expectedCLAUSE, clause)
+	go createPaginationClause(pageNumber, pageSize, c)
+	clause := <-c
+	expectedCLAUSE := " LIMIT 30 OFFSET 30"
+	if clause != expectedCLAUSE {
+		t.Errorf("expected clause should be %v; got %v", expectedCLAUSE, clause)
+	}
+}
+
+func TestCreatePaginationClause_with_page_eq_1(t *testing.T) {
+	pageNumber := 1
+	pageSize := 30
+	c := make(chan string)
+	go createPaginationClause(pageNumber, pageSize, c)
+	clause := <-c
+	expectedCLAUSE := " LIMIT 30 OFFSET 0"
+	if clause != expectedCLAUSE {
+		t.Errorf("expected clause should be %v; got %v", expectedCLAUSE, clause)
+	}
+}
+
+func TestCreatePaginationClause_with_page_lt_1(t *testing.T) {
+	pageNumber := -4
+	pageSize := 30
+	c := make(chan string)
+	go createPaginationClause(pageNumber, pageSize, c)
+	clause := <-c
+	expectedCLAUSE := " LIMIT 30 OFFSET 0"
+	if clause != expectedCLAUSE {
+		t.Errorf("expected clause should be %v; got %v", expectedCLAUSE, clause)
+	}
+}
+
+func TestCreateOrderByClause_with_sorting_options(t *testing.T) {
+	colNames := []string{"id", "name", "lastname", "age", "address"}
+	params := parameters{{"sort", "=", "+name,-lastname,-age,+address"}}
+	c := make(chan string)
+	go createOrderByClause("postgres", params, "sort", colNames, customOrderByClauses{}, []string{"id"}, nil, false, c)
+	clause := <-c
+	expectedCLAUSE := " ORDER BY name ASC,lastname DESC,age DESC,address ASC,id"
+	if clause != expectedCLAUSE {
+		t.Errorf("expected clause should be %v, got %v", expectedCLAUSE, clause)
+	}
+}
+
+func TestCreateOrderByClause_with_custom_clauses_and_sorting_options(t *testing.T) {
+	colNames := []string{"id", "name", "salary"}
+	params := parameters{{"sort", "=", "+name,+salary"}}
+	customs := customOrderByClauses{{column: "salary", sorting: "DESC"}}
+	c := make(chan string)
+	go createOrderByClause("postgres", params, "sort", colNames, customs, []string{"id"}, nil, false, c)
+	clause := <-c
+	expectedCLAUSE := " ORDER BY salary DESC,name ASC,id"
+	if clause != expectedCLAUSE {
+		t.Errorf("expected clause should be %v, got %v", expectedCLAUSE, clause)
+	}
+}
+
+func TestPaginate_DefaultOrderBy(t *testing.T) {
+	type Employee struct {
+		ID        int `paginate:"id"`
+		Name      string
+		CreatedAt time.Time
+	}
+
+	tests := []struct {
+		query    string
+		opts     []Option
+		expected string
+	}{
+		{
+			query:    "",
+			opts:     []Option{DefaultOrderBy("created_at", "desc")},
+			expected: "ORDER BY created_at DESC,id",
+		},
+		{
+			query:    "sort=+name",
+			opts:     []Option{DefaultOrderBy("created_at", "DESC")},
+			expected: "ORDER BY name ASC,id",
+		},
+		{
+			query:    "sort=+unknown",
+			opts:     []Option{DefaultOrderBy("created_at", "DESC")},
+			expected: "ORDER BY created_at DESC,id",
+		},
+		{
+			query:    "",
+			opts:     []Option{DefaultOrderBy("created_at", "DESC"), OrderByAsc("name"), DefaultOrderBy("id", "DESC")},
+			expected: "ORDER BY name ASC,created_at DESC,id",
+		},
+		{
+			query:    "sort=-name",
+			opts:     []Option{DefaultOrderBy("created_at", "DESC"), OrderByAsc("created_at")},
+			expected: "ORDER BY created_at ASC,name DESC,id",
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "SELECT id, name, created_at, count(*) over() FROM employee " + tt.expected + " LIMIT 30 OFFSET 0"
+		if cmd != expected {
+			t.Errorf("expected sql command for %q to be %q; got %q", tt.query, expected, cmd)
+		}
+	}
+
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewPaginator(Employee{}, "postgres", *u, DefaultOrderBy("created_at", "LATEST")); err == nil {
+		t.Error("expected an error with an invalid sorting direction")
+	}
+	if _, err = NewPaginator(Employee{}, "postgres", *u, DefaultOrderBy("updated_at", "DESC")); err == nil {
+		t.Error("expected an error with an unknown column")
+	}
+}
+
+func TestPaginate_OrderByDesc_With_Sort_Parameter(t *testing.T) {
+	type Employee struct {
+		ID     int `paginate:"id"`
+		Name   string
+		Salary float64
+	}
+	u, err := url.Parse("http://ottotech.com?sort=+name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "mysql", *u, OrderByDesc("salary"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, salary, count(*) over() FROM employee ORDER BY salary DESC,name ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestPaginate_Sort_By_ID_Descending(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "sort=-id",
+			expected: "SELECT id, name, count(*) over() FROM employee ORDER BY id DESC LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "sort=-id,+name",
+			expected: "SELECT id, name, count(*) over() FROM employee ORDER BY id DESC,name ASC LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "sort=+name,-id",
+			expected: "SELECT id, name, count(*) over() FROM employee ORDER BY name ASC,id DESC LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "mysql", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command for %s to be %q; got %q", tt.query, tt.expected, cmd)
+		}
+	}
+
+	// The keyset pagination should fetch the records with smaller ids.
+	u, err := url.Parse("http://ottotech.com?sort=-id&cursor=" + encodeCursor([]interface{}{5}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "mysql", *u, KeysetPagination("cursor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM employee WHERE (id < ?) ORDER BY id DESC LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 1 || fmt.Sprint(args[0]) != "5" {
+		t.Errorf("expected args to be [5]; got %v", args)
+	}
+}
+
+func TestCreateOrderByClause_With_ID_Sort_Directives(t *testing.T) {
+	colNames := []string{"id", "name"}
+	tests := []struct {
+		sort     string
+		expected string
+	}{
+		{sort: "+id", expected: " ORDER BY id ASC"},
+		{sort: "-id", expected: " ORDER BY id DESC"},
+	}
+	for _, tt := range tests {
+		params := parameters{{name: "sort", sign: eq, value: tt.sort}}
+		c := make(chan string)
+		go createOrderByClause("postgres", params, "sort", colNames, customOrderByClauses{}, []string{"id"}, nil, false, c)
+		clause := <-c
+		if clause != tt.expected {
+			t.Errorf("expected clause for %s should be %v, got %v", tt.sort, tt.expected, clause)
+		}
+	}
+}
+
+func TestPaginate_Composite_ID(t *testing.T) {
+	type TenantItem struct {
+		TenantID int    `paginate:"id;col=tenant_id"`
+		ItemID   int    `paginate:"id;col=item_id"`
+		Name     string `paginate:"filter"`
+	}
+
+	tests := []struct {
+		query    string
+		fields   bool
+		expected string
+	}{
+		{
+			query:    "name=rob",
+			expected: "SELECT tenant_id, item_id, name, count(*) over() FROM tenant_item WHERE name = $1 ORDER BY tenant_id,item_id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "sort=-name",
+			expected: "SELECT tenant_id, item_id, name, count(*) over() FROM tenant_item ORDER BY name DESC,tenant_id,item_id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "sort=-item_id",
+			expected: "SELECT tenant_id, item_id, name, count(*) over() FROM tenant_item ORDER BY item_id DESC,tenant_id LIMIT 30 OFFSET 0",
+		},
+		{
+			// All the id columns should always be selected.
+			query:    "fields=name",
+			fields:   true,
+			expected: "SELECT tenant_id, item_id, name, count(*) over() FROM tenant_item ORDER BY tenant_id,item_id LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := []Option{}
+		if tt.fields {
+			opts = append(opts, FieldsParam("fields"))
+		}
+		paginator, err := NewPaginator(TenantItem{}, "postgres", *u, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command for %s to be %q; got %q", tt.query, tt.expected, cmd)
+		}
+	}
+
+	// The keyset pagination should continue after both id columns.
+	u, err := url.Parse("http://ottotech.com?cursor=" + encodeCursor([]interface{}{1, 7}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(TenantItem{}, "mysql", *u, KeysetPagination("cursor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT tenant_id, item_id, name, count(*) over() FROM tenant_item " +
+		"WHERE (tenant_id > ? OR (tenant_id = ? AND item_id > ?)) ORDER BY tenant_id,item_id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	if _, err = NewPaginator(TenantItem{}, "postgres", *u, ExcludeColumns("ItemID")); err == nil {
+		t.Error("expected an error when excluding a column of the composite id")
+	}
+
+	db, _ := newFakeDB([]string{"tenant_id", "item_id"}, nil)
+	defer db.Close()
+	if _, err = paginator.IDs(context.Background(), db); err == nil {
+		t.Error("expected an error when calling IDs with a composite id")
+	}
+}
+
+func TestCreateOrderByClause_with_no_sorting_options(t *testing.T) {
+	colNames := []string{"name", "lastname", "age", "address"}
+	params := parameters{}
+	c := make(chan string)
+	go createOrderByClause("postgres", params, "sort", colNames, customOrderByClauses{}, []string{"id"}, nil, false, c)
+	clause := <-c
+	expectedCLAUSE := " ORDER BY id"
+	if clause != expectedCLAUSE {
+		t.Errorf("expected clause should be %v, got %v", expectedCLAUSE, clause)
+	}
+}
+
+func TestPaginate_OrderByCase_Args_Follow_Where_Args(t *testing.T) {
+	type Person struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?last_name=Smith&page=2&page_size=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Person{}, "postgres", *u, OrderByCase("name", "Mark", "Erika"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT id, name, last_name, count(*) over() FROM person WHERE last_name = $1 " +
+		"ORDER BY CASE WHEN name = $2 THEN 0 WHEN name = $3 THEN 1 ELSE 2 END ASC,id LIMIT 5 OFFSET 5"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+	expectedArgs := []interface{}{"Smith", "Mark", "Erika"}
+	if len(args) != len(expectedArgs) {
+		t.Fatalf("expected %d args; got %d", len(expectedArgs), len(args))
+	}
+	for i := range expectedArgs {
+		if args[i] != expectedArgs[i] {
+			t.Errorf("arg number %d should be %v; got %v", i+1, expectedArgs[i], args[i])
+		}
+	}
+
+	paginator, err = NewPaginator(Person{}, "mysql", *u, OrderByCase("name", "Mark"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL = "SELECT id, name, last_name, count(*) over() FROM person WHERE last_name = ? " +
+		"ORDER BY CASE WHEN name = ? THEN 0 ELSE 1 END ASC,id LIMIT 5 OFFSET 5"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+	if len(args) != 2 || args[0] != "Smith" || args[1] != "Mark" {
+		t.Errorf("expected args [Smith Mark]; got %v", args)
+	}
+}
+
+func TestPaginate_OrderByCase_Column(t *testing.T) {
+	type Person struct {
+		ID    int    `paginate:"id"`
+		Name  string `paginate:"filter"`
+		Order int    `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewPaginator(Person{}, "postgres", *u, OrderByCase("password_hash", "x")); err == nil {
+		t.Error("expected an error when sorting by the case of a column that does not exist")
+	}
+
+	paginator, err := NewPaginator(Person{}, "postgres", *u, QuoteIdentifiers(), OrderByCase("order", 2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := `SELECT "id", "name", "order", count(*) over() FROM "person" ` +
+		`ORDER BY CASE WHEN "order" = $1 THEN 0 WHEN "order" = $2 THEN 1 ELSE 2 END ASC,"id" LIMIT 30 OFFSET 0`
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+}
+
+func TestPaginate_Search_Multiple_Terms(t *testing.T) {
+	type Person struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string
+	}
+	u, err := url.Parse("http://ottotech.com?name=Bill&q=smith&q=ga_tes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Person{}, "postgres", *u, Search("q", "name", "last_name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT id, name, last_name, count(*) over() FROM person WHERE name = $1 AND " +
+		"(name ILIKE ANY(ARRAY[$2,$3]) OR last_name ILIKE ANY(ARRAY[$4,$5])) ORDER BY id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+	expectedArgs := []interface{}{"Bill", "%smith%", `%ga\_tes%`, "%smith%", `%ga\_tes%`}
+	if fmt.Sprint(args) != fmt.Sprint(expectedArgs) {
+		t.Errorf("expected args %v; got %v", expectedArgs, args)
+	}
+
+	paginator, err = NewPaginator(Person{}, "mysql", *u, Search("q", "last_name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL = "SELECT id, name, last_name, count(*) over() FROM person WHERE name = ? AND " +
+		"(last_name LIKE ? OR last_name LIKE ?) ORDER BY id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+	if len(args) != 3 {
+		t.Errorf("expected 3 args; got %v", args)
+	}
+
+	_, err = NewPaginator(Person{}, "postgres", *u, Search("q", "unknown"))
+	if err == nil {
+		t.Error("expected an error when searching a column that does not exist")
+	}
+}
+
+func TestAddJoinClause_Multiple_Conditions(t *testing.T) {
+	type Employee struct {
+		ID       int `paginate:"id"`
+		TenantID int `paginate:"col=tenant_id"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	join, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "managers", "employee_id").AndOn(" tenant_id ", "tenant_id")
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+
+	sql, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT employees.id, employees.tenant_id, count(*) over() FROM employees JOIN managers ON " +
+		"employees.id = managers.employee_id AND employees.tenant_id = managers.tenant_id ORDER BY id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+
+	join, err = NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "developers", "employee_id").AndOn("unknown", "tenant_id")
+	if err = paginator.AddJoinClause(join); err == nil {
+		t.Error("expected an error when a join condition references an unknown column")
+	}
+}
+
+func TestAddJoinClause_Dialect_Mismatch_And_Insertion_Order(t *testing.T) {
+	type Employee struct {
+		ID int `paginate:"id"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mysqlJoin, err := NewInnerJoinClause("mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mysqlJoin.On("id", "manager", "employee_id")
+	if err = paginator.AddJoinClause(mysqlJoin); err == nil {
+		t.Error("expected an error when the join clause dialect does not match the paginator dialect")
+	}
+
+	leftJoin, err := NewLeftJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leftJoin.On("id", "developer", "employee_id")
+	if err = paginator.AddJoinClause(leftJoin); err != nil {
+		t.Fatal(err)
+	}
+
+	innerJoin, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	innerJoin.On("id", "manager", "employee_id")
+	if err = paginator.AddJoinClause(innerJoin); err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.AddJoinClause(innerJoin); err == nil {
+		t.Error("expected an error when the same join clause is given twice")
+	}
+
+	sql, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT employees.id, count(*) over() FROM employees LEFT JOIN developer ON employees.id = developer.employee_id " +
+		"JOIN manager ON employees.id = manager.employee_id ORDER BY id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+}
+
+func TestNewPaginatorFromRequest(t *testing.T) {
+	type Person struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string `paginate:"filter"`
+	}
+	r := httptest.NewRequest(http.MethodGet, "http://ottotech.com/people?name=Ringo&last_name<>Star&page=3&page_size=10", nil)
+
+	paginator, err := NewPaginatorFromRequest(Person{}, "postgres", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT id, name, last_name, count(*) over() FROM person WHERE name = $1 AND last_name <> $2 ORDER BY id LIMIT 10 OFFSET 20"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+	if len(args) != 2 || args[0] != "Ringo" || args[1] != "Star" {
+		t.Errorf("expected args [Ringo Star]; got %v", args)
+	}
+
+	if _, err = NewPaginatorFromRequest(Person{}, "postgres", nil); err == nil {
+		t.Error("expected an error when the given request is nil")
+	}
+}
+
+// scanFakeRows runs the sql command generated by the given paginator against
+// the given db and scans the returned rows with GetRowPtrArgs.
+func scanFakeRows(t *testing.T, db *sql.DB, paginator Paginator) {
+	t.Helper()
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Query(cmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Scan(paginator.GetRowPtrArgs()...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPaginator_LastModified(t *testing.T) {
+	type Article struct {
+		ID        int       `paginate:"id"`
+		Title     string    `paginate:"filter"`
+		UpdatedAt time.Time `paginate:"col=updated_at"`
+		Published NullTime  `paginate:"col=published_at"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t1 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+	t3 := time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC)
+	db, _ := newFakeDB(
+		[]string{"id", "title", "updated_at", "published_at", "count"},
+		[][]driver.Value{
+			{int64(1), "a", t1, nil, int64(3)},
+			{int64(2), "b", t2, t1, int64(3)},
+			{int64(3), "c", t3, nil, int64(3)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Article{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFakeRows(t, db, paginator)
+	lastModified, ok := paginator.LastModified()
+	if !ok {
+		t.Fatal("expected to have a last modified timestamp")
+	}
+	if !lastModified.Equal(t2) {
+		t.Errorf("expected last modified to be %s; got %s", t2, lastModified)
+	}
+
+	paginator, err = NewPaginator(Article{}, "postgres", *u, LastModifiedColumn("published_at"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFakeRows(t, db, paginator)
+	lastModified, ok = paginator.LastModified()
+	if !ok || !lastModified.Equal(t1) {
+		t.Errorf("expected last modified to be %s; got %s (%v)", t1, lastModified, ok)
+	}
+
+	paginator, err = NewPaginator(Article{}, "postgres", *u, LastModifiedColumn("unknown"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFakeRows(t, db, paginator)
+	if _, ok = paginator.LastModified(); ok {
+		t.Error("expected no last modified timestamp when the column does not exist")
+	}
+
+	// LastModified can be called while the rows are scanned. Run with
+	// -race to detect unguarded state transitions.
+	paginator, err = NewPaginator(Article{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFakeRows(t, db, paginator)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			paginator.LastModified()
+		}()
+		go func() {
+			defer wg.Done()
+			for paginator.NextData() {
+				if err := paginator.Scan(&Article{}); err != nil && !errors.Is(err, ErrPaginatorIsClosed) {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if lastModified, ok = paginator.LastModified(); !ok || !lastModified.Equal(t2) {
+		t.Errorf("expected last modified to be %s; got %s (%v)", t2, lastModified, ok)
+	}
+}
+
+func TestPaginate_Qualifies_Columns_When_Joining_Tables(t *testing.T) {
+	type Employee struct {
+		ID                  int    `paginate:"id"`
+		Name                string `paginate:"filter"`
+		TenantID            int    `paginate:"col=tenant_id"`
+		ManagerTenantID     int    `paginate:"col=manager.tenant_id"`
+		ProgrammingLanguage string `paginate:"filter;col=developer.programming_language"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo&programming_language=Go&sort=+name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without join clauses the columns are not qualified.
+	sql, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT id, name, tenant_id, manager.tenant_id, developer.programming_language, count(*) over() FROM employees " +
+		"WHERE name = $1 AND developer.programming_language = $2 ORDER BY name ASC,id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+
+	join, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "manager", "employee_id")
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+
+	sql, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL = "SELECT employees.id, employees.name, employees.tenant_id, manager.tenant_id, developer.programming_language, count(*) over() " +
+		"FROM employees JOIN manager ON employees.id = manager.employee_id " +
+		"WHERE employees.name = $1 AND developer.programming_language = $2 ORDER BY name ASC,id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+	if len(args) != 2 || args[0] != "Ringo" || args[1] != "Go" {
+		t.Errorf("expected args [Ringo Go]; got %v", args)
+	}
+}
+
+func TestPaginator_DebugSQL(t *testing.T) {
+	type Employee struct {
+		ID         int       `paginate:"id"`
+		Name       string    `paginate:"filter"`
+		Salary     float64   `paginate:"filter"`
+		DateJoined time.Time `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=O'Brien&name=Ringo&salary>=4000&date_joined<2021-03-01T10:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, Location(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, salary, date_joined, count(*) over() FROM employee WHERE name IN('O''Brien','Ringo') AND " +
+		"salary >= '4000' AND date_joined < '2021-03-01 10:00:00+00:00' ORDER BY id LIMIT 30 OFFSET 0"
+	if sql := paginator.DebugSQL(); sql != expected {
+		t.Errorf("expected debug sql to be %q; got %q", expected, sql)
+	}
+
+	paginator, err = NewPaginator(Employee{}, "mysql", *u, Location(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, salary, date_joined, count(*) over() FROM employee WHERE name IN('O''Brien','Ringo') AND " +
+		"salary >= '4000' AND date_joined < '2021-03-01 10:00:00+00:00' ORDER BY id LIMIT 30 OFFSET 0"
+	if sql := paginator.DebugSQL(); sql != expected {
+		t.Errorf("expected debug sql to be %q; got %q", expected, sql)
+	}
+}
+
+func TestSQLLiteral(t *testing.T) {
+	var nilString *string
+	name := "Ringo"
+	tests := []struct {
+		arg      interface{}
+		expected string
+	}{
+		{arg: nil, expected: "NULL"},
+		{arg: nilString, expected: "NULL"},
+		{arg: &name, expected: "'Ringo'"},
+		{arg: "it's", expected: "'it''s'"},
+		{arg: []byte("abc"), expected: "'abc'"},
+		{arg: 42, expected: "42"},
+		{arg: 4650.9, expected: "4650.9"},
+		{arg: true, expected: "TRUE"},
+		{arg: NullString{}, expected: "NULL"},
+		{arg: time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC), expected: "'2021-03-01 10:30:00+00:00'"},
+	}
+	for _, tt := range tests {
+		if got := sqlLiteral(tt.arg, "postgres"); got != tt.expected {
+			t.Errorf("expected the sql literal of %v to be %s; got %s", tt.arg, tt.expected, got)
+		}
+	}
+
+	// Mysql and mariadb take the backslashes as escape characters.
+	tests = []struct {
+		arg      interface{}
+		expected string
+	}{
+		{arg: `C:\dir`, expected: `'C:\\dir'`},
+		{arg: `it\'s`, expected: `'it\\''s'`},
+	}
+	for _, dialect := range []string{"mysql", "mariadb"} {
+		for _, tt := range tests {
+			if got := sqlLiteral(tt.arg, dialect); got != tt.expected {
+				t.Errorf("expected the %s sql literal of %v to be %s; got %s", dialect, tt.arg, tt.expected, got)
+			}
+		}
+	}
+	if got := sqlLiteral(`C:\dir`, "postgres"); got != `'C:\dir'` {
+		t.Errorf("expected the postgres sql literal to keep the backslash; got %s", got)
+	}
+}
+
+func TestPaginator_Execute_Skips_Queries_When_Filters_Match_Nothing(t *testing.T) {
+	type Person struct {
+		ID     int    `paginate:"id"`
+		Name   string `paginate:"filter"`
+		Salary int    `paginate:"filter"`
+	}
+	// The salary range is inverted, so no record can match it.
+	u, err := url.Parse("http://ottotech.com?name=Ringo&name=John&salary=8000..4000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, conn := newFakeDB(
+		[]string{"id", "name", "salary"},
+		[][]driver.Value{
+			{int64(1), "Ringo", int64(5000)},
+		},
+	)
+	defer db.Close()
+
+	pag, err := NewPaginator(Person{}, "mariadb", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = pag.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	if queries := conn.executedQueries(); len(queries) != 0 {
+		t.Errorf("expected no queries to be executed; got %v", queries)
+	}
+	if pag.NextData() {
+		t.Errorf("expected the page to be empty")
+	}
+
+	response := pag.Response()
+	if response.TotalSize != 0 || response.HasNextPage || response.Partial {
+		t.Errorf("expected a complete empty response; got %+v", response)
+	}
+
+	ids, err := pag.IDs(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected no ids; got %v", ids)
+	}
+	if queries := conn.executedQueries(); len(queries) != 0 {
+		t.Errorf("expected no queries to be executed; got %v", queries)
+	}
+
+	count, err := pag.Count(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected a count of 0; got %d", count)
+	}
+
+	// With OR the other filters can still match records.
+	pag, err = NewPaginator(Person{}, "mariadb", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = pag.SetFilterConjunction("OR"); err != nil {
+		t.Fatal(err)
+	}
+	if err = pag.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	if queries := conn.executedQueries(); len(queries) == 0 {
+		t.Errorf("expected the queries to be executed")
+	}
+}
+
+func TestPaginator_Execute(t *testing.T) {
+	type Person struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo&name=John")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, conn := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{
+			{int64(1), "Ringo", int64(2)},
+			{int64(4), "John", int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Person{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := conn.executedQueries()
+	expectedSQL := "SELECT id, name, count(*) over() FROM person WHERE name IN($1,$2) ORDER BY id LIMIT 30 OFFSET 0"
+	if len(queries) != 1 || queries[0] != expectedSQL {
+		t.Errorf("expected the query %q to be executed; got %v", expectedSQL, queries)
+	}
+
+	results := make([]Person, 0)
+	for paginator.NextData() {
+		person := Person{}
+		if err = paginator.Scan(&person); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, person)
+	}
+	if len(results) != 2 || results[0].Name != "Ringo" || results[1].Name != "John" {
+		t.Errorf("expected Ringo and John in results; got %+v", results)
+	}
+	if response := paginator.Response(); response.TotalSize != 2 || response.PageCount != 2 {
+		t.Errorf("expected a total size and page count of 2; got %+v", response)
+	}
+
+	// A cancelled context should stop the execution.
+	paginator, err = NewPaginator(Person{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = paginator.Execute(ctx, db); err == nil {
+		t.Error("expected an error when executing with a cancelled context")
+	}
+}
+
+func TestPaginate_NullsLastByDefault(t *testing.T) {
+	type Employee struct {
+		ID        int        `paginate:"id"`
+		Name      string     `paginate:"filter"`
+		NullInt   NullInt    `paginate:"col=null_int"`
+		NullText  NullString `paginate:"col=null_text"`
+		NullFloat NullFloat64
+		NullDate  *time.Time `paginate:"col=null_date"`
+	}
+	u, err := url.Parse("http://ottotech.com?sort=-null_int,+name,+null_text,-null_float,+null_date")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"), NullsLastByDefault(), NullsFirst("null_float"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT id, name, null_int, null_text, null_float, null_date, count(*) over() FROM employees " +
+		"ORDER BY null_int DESC NULLS LAST,name ASC,null_text ASC NULLS LAST,null_float DESC NULLS FIRST,null_date ASC NULLS LAST,id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+
+	paginator, err = NewPaginator(Employee{}, "mysql", *u, TableName("employees"), NullsLastByDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL = "SELECT id, name, null_int, null_text, null_float, null_date, count(*) over() FROM employees " +
+		"ORDER BY null_int IS NULL ASC,null_int DESC,name ASC,null_text IS NULL ASC,null_text ASC," +
+		"null_float IS NULL ASC,null_float DESC,null_date IS NULL ASC,null_date ASC,id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+
+	// Without the option the sorting should not be affected.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL = "SELECT id, name, null_int, null_text, null_float, null_date, count(*) over() FROM employees " +
+		"ORDER BY null_int DESC,name ASC,null_text ASC,null_float DESC,null_date ASC,id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+}
+
+func TestPaginator_IDs(t *testing.T) {
+	type Person struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+		Age  int    `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?age>30&sort=-name&page=2&page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, conn := newFakeDB(
+		[]string{"id"},
+		[][]driver.Value{{int64(8)}, {int64(3)}},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Person{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := paginator.IDs(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := conn.executedQueries()
+	expectedSQL := "SELECT id FROM person WHERE age > $1 ORDER BY name DESC,id LIMIT 2 OFFSET 2"
+	if len(queries) != 1 || queries[0] != expectedSQL {
+		t.Errorf("expected the query %q to be executed; got %v", expectedSQL, queries)
+	}
+	if len(ids) != 2 || ids[0] != 8 || ids[1] != 3 {
+		t.Errorf("expected ids [8 3] of type int; got %#v", ids)
+	}
+}
+
+func TestPaginator_Scan_Pointer_Fields(t *testing.T) {
+	type Employee struct {
+		ID         int        `paginate:"id"`
+		Name       *string    `paginate:"col=name"`
+		Age        *int       `paginate:"col=age"`
+		Number     *int64     `paginate:"col=number"`
+		Salary     *float64   `paginate:"col=salary"`
+		Active     *bool      `paginate:"col=active"`
+		DateJoined *time.Time `paginate:"col=date_joined"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t1 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	db, _ := newFakeDB(
+		[]string{"id", "name", "age", "number", "salary", "active", "date_joined", "count"},
+		[][]driver.Value{
+			{int64(1), "Rob", int64(40), int64(1001), float64(2500.5), true, t1, int64(2)},
+			{int64(2), nil, nil, nil, nil, nil, nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFakeRows(t, db, paginator)
+
+	results := make([]Employee, 0)
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, e)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results; got %d", len(results))
+	}
+
+	rob := results[0]
+	if rob.Name == nil || *rob.Name != "Rob" {
+		t.Errorf("expected name to be Rob; got %v", rob.Name)
+	}
+	if rob.Age == nil || *rob.Age != 40 {
+		t.Errorf("expected age to be 40; got %v", rob.Age)
+	}
+	if rob.Number == nil || *rob.Number != 1001 {
+		t.Errorf("expected number to be 1001; got %v", rob.Number)
+	}
+	if rob.Salary == nil || *rob.Salary != 2500.5 {
+		t.Errorf("expected salary to be 2500.5; got %v", rob.Salary)
+	}
+	if rob.Active == nil || !*rob.Active {
+		t.Errorf("expected active to be true; got %v", rob.Active)
+	}
+	if rob.DateJoined == nil || !rob.DateJoined.Equal(t1) {
+		t.Errorf("expected date joined to be %s; got %v", t1, rob.DateJoined)
+	}
+
+	empty := results[1]
+	if empty.Name != nil || empty.Age != nil || empty.Number != nil || empty.Salary != nil ||
+		empty.Active != nil || empty.DateJoined != nil {
+		t.Errorf("expected NULL values to be scanned as nil pointers; got %+v", empty)
+	}
+}
+
+func TestPaginator_Scan_Nullable_Small_Integers(t *testing.T) {
+	type Employee struct {
+		ID       int   `paginate:"id"`
+		Level    int8  `paginate:"col=level"`
+		Priority int16 `paginate:"col=priority"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, _ := newFakeDB(
+		[]string{"id", "level", "priority", "count"},
+		[][]driver.Value{
+			{int64(1), int64(3), int64(12), int64(2)},
+			{int64(2), nil, nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFakeRows(t, db, paginator)
+
+	results := make([]Employee, 0)
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, e)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results; got %d", len(results))
+	}
+	if results[0].Level != 3 || results[0].Priority != 12 {
+		t.Errorf("expected level 3 and priority 12; got %d and %d", results[0].Level, results[0].Priority)
+	}
+	if results[1].Level != 0 || results[1].Priority != 0 {
+		t.Errorf("expected NULL values to be scanned as zero; got %d and %d", results[1].Level, results[1].Priority)
+	}
+}
+
+func TestPaginate_OrderBySeededRandom(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, OrderBySeededRandom(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM employee WHERE name = $1 ORDER BY md5(id::text || $2) ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 2 || args[0] != "rob" || args[1] != "42" {
+		t.Errorf("expected args to be [rob 42]; got %v", args)
+	}
+
+	paginator, err = NewPaginator(Employee{}, "mysql", *u, OrderBySeededRandom(-7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, count(*) over() FROM employee WHERE name = ? ORDER BY md5(concat(id, ?)) ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 2 || args[1] != "-7" {
+		t.Errorf("expected the seed arg to be -7; got %v", args)
+	}
+
+	// With join clauses the id column should be qualified, since the
+	// joined table might have an id column too.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, OrderBySeededRandom(42), TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	join, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "manager", "employee_id")
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT employees.id, employees.name, count(*) over() FROM employees JOIN manager ON employees.id = manager.employee_id WHERE employees.name = $1 ORDER BY md5(employees.id::text || $2) ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// With QuoteIdentifiers the id column should be quoted.
+	paginator, err = NewPaginator(Employee{}, "mysql", *u, OrderBySeededRandom(-7), QuoteIdentifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT `id`, `name`, count(*) over() FROM `employee` WHERE `name` = ? ORDER BY md5(concat(`id`, ?)) ASC,`id` LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestPaginator_Scan_Uint_Fields(t *testing.T) {
+	type Counter struct {
+		ID    uint   `paginate:"id"`
+		Hits  uint16 `paginate:"col=hits"`
+		Total uint64 `paginate:"col=total"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, _ := newFakeDB(
+		[]string{"id", "hits", "total", "count"},
+		[][]driver.Value{
+			{int64(1), int64(65535), int64(1 << 40), int64(2)},
+			{int64(2), nil, nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Counter{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFakeRows(t, db, paginator)
+
+	results := make([]Counter, 0)
+	for paginator.NextData() {
+		c := Counter{}
+		if err = paginator.Scan(&c); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, c)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results; got %d", len(results))
+	}
+	if results[0].ID != 1 || results[0].Hits != 65535 || results[0].Total != 1<<40 {
+		t.Errorf("expected the first counter to be {1 65535 %d}; got %+v", uint64(1<<40), results[0])
+	}
+	if results[1].ID != 2 || results[1].Hits != 0 || results[1].Total != 0 {
+		t.Errorf("expected NULL values to be scanned as zero; got %+v", results[1])
+	}
+}
+
+func TestPaginator_Scan_Uint_Fields_Out_Of_Range(t *testing.T) {
+	type Counter struct {
+		ID   uint   `paginate:"id"`
+		Hits uint16 `paginate:"col=hits"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, hits := range []int64{-1, 65536} {
+		db, _ := newFakeDB(
+			[]string{"id", "hits", "count"},
+			[][]driver.Value{{int64(1), hits, int64(1)}},
+		)
+
+		paginator, err := NewPaginator(Counter{}, "postgres", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := db.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+			err = rows.Scan(paginator.GetRowPtrArgs()...)
+		}
+		if err == nil {
+			t.Errorf("expected an error when scanning %d into an uint16 field", hits)
+		}
+		rows.Close()
+		db.Close()
+	}
+}
+
+func TestPaginator_Scan_Int_Fields_Out_Of_Range(t *testing.T) {
+	type Employee struct {
+		ID    int   `paginate:"id"`
+		Level int8  `paginate:"col=level"`
+		Rank  int16 `paginate:"col=rank"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		level, rank int64
+		valid       bool
+	}{
+		{level: 127, rank: -32768, valid: true},
+		{level: 300, rank: 1, valid: false},
+		{level: -129, rank: 1, valid: false},
+		{level: 1, rank: 32768, valid: false},
+	}
+	for _, tt := range tests {
+		db, _ := newFakeDB(
+			[]string{"id", "level", "rank", "count"},
+			[][]driver.Value{{int64(1), tt.level, tt.rank, int64(1)}},
+		)
+
+		paginator, err := NewPaginator(Employee{}, "postgres", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := db.Query(cmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+			err = rows.Scan(paginator.GetRowPtrArgs()...)
+		}
+		rows.Close()
+		db.Close()
+
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("expected an error when scanning level %d and rank %d", tt.level, tt.rank)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := Employee{}
+		for paginator.NextData() {
+			if err = paginator.Scan(&e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if int64(e.Level) != tt.level || int64(e.Rank) != tt.rank {
+			t.Errorf("expected level %d and rank %d; got %+v", tt.level, tt.rank, e)
+		}
+	}
+}
+
+func TestPaginate_FullTextSearch(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id"`
+		Name  string `paginate:"filter"`
+		Notes string `paginate:"col=notes"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&q=golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weights := map[string]string{"notes": "B", "name": "A"}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, FullTextSearch("q", weights))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := "(setweight(to_tsvector(coalesce(name::text, '')), 'A') || setweight(to_tsvector(coalesce(notes::text, '')), 'B'))"
+	expected := "SELECT id, name, notes, count(*) over() FROM employee WHERE name = $1 AND (" + document +
+		" @@ websearch_to_tsquery($2)) ORDER BY ts_rank(" + document + ", websearch_to_tsquery($3)) DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 3 || args[0] != "rob" || args[1] != "golang" || args[2] != "golang" {
+		t.Errorf("expected args to be [rob golang golang]; got %v", args)
+	}
+
+	// With join clauses and QuoteIdentifiers the searched columns should
+	// be qualified and quoted like the rest of the columns.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, FullTextSearch("q", weights), QuoteIdentifiers(), TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	join, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "manager", "employee_id")
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	document = `(setweight(to_tsvector(coalesce("employees"."name"::text, '')), 'A') || setweight(to_tsvector(coalesce("employees"."notes"::text, '')), 'B'))`
+	expected = `SELECT "employees"."id", "employees"."name", "employees"."notes", count(*) over() FROM "employees" ` +
+		`JOIN "manager" ON "employees"."id" = "manager"."employee_id" WHERE "employees"."name" = $1 AND (` + document +
+		` @@ websearch_to_tsquery($2)) ORDER BY ts_rank(` + document + `, websearch_to_tsquery($3)) DESC,"id" LIMIT 30 OFFSET 0`
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	if _, err = NewPaginator(Employee{}, "mysql", *u, FullTextSearch("q", weights)); err == nil {
+		t.Error("expected an error when using full-text search with mysql")
+	}
+	if _, err = NewPaginator(Employee{}, "postgres", *u, FullTextSearch("q", map[string]string{"name": "E"})); err == nil {
+		t.Error("expected an error with an invalid weight")
+	}
+	if _, err = NewPaginator(Employee{}, "postgres", *u, FullTextSearch("q", map[string]string{"unknown": "A"})); err == nil {
+		t.Error("expected an error with an unknown column")
+	}
+}
+
+func TestPaginate_FullTextSearch_Without_Query(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id"`
+		Name  string `paginate:"filter"`
+		Notes string `paginate:"col=notes"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&q=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, FullTextSearch("q", map[string]string{"notes": "A"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, notes, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 1 || args[0] != "rob" {
+		t.Errorf("expected args to be [rob]; got %v", args)
+	}
+}
+
+func TestPaginate_PlainFullTextSearch(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id"`
+		Name  string `paginate:"filter"`
+		Notes string `paginate:"col=notes"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&q=golang+developer&q=remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, PlainFullTextSearch("notes", "q"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The search query should be numbered after the filters and every
+	// word of the repeated parameters should match.
+	document := "(setweight(to_tsvector(coalesce(notes::text, '')), 'A'))"
+	expected := "SELECT id, name, notes, count(*) over() FROM employee WHERE name = $1 AND (" + document +
+		" @@ plainto_tsquery($2)) ORDER BY ts_rank(" + document + ", plainto_tsquery($3)) DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[rob golang developer remote golang developer remote]" {
+		t.Errorf("expected args to be [rob golang developer remote golang developer remote]; got %v", args)
+	}
+
+	if _, err = NewPaginator(Employee{}, "mysql", *u, PlainFullTextSearch("notes", "q")); err == nil {
+		t.Error("expected an error when using full-text search with mysql")
+	}
+	if _, err = NewPaginator(Employee{}, "postgres", *u, PlainFullTextSearch("unknown", "q")); err == nil {
+		t.Error("expected an error with an unknown column")
+	}
+}
+
+func TestPaginator_QueryShape(t *testing.T) {
+	type Employee struct {
+		ID     int     `paginate:"id"`
+		Name   string  `paginate:"filter"`
+		Salary float64 `paginate:"filter"`
+	}
+
+	shape := func(dialect, rawURL string) string {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, dialect, *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return paginator.QueryShape()
+	}
+
+	s1 := shape("postgres", "http://ottotech.com?name=rob&salary>=4000&page=1")
+	s2 := shape("postgres", "http://ottotech.com?name=ringo&salary>=9000&page=3")
+	if s1 != s2 {
+		t.Errorf("expected queries differing only in arg values to have the same shape; got %q and %q", s1, s2)
+	}
+	expected := "SELECT id, name, salary, count(*) over() FROM employee WHERE name = ? AND salary >= ? ORDER BY id LIMIT ? OFFSET ?"
+	if s1 != expected {
+		t.Errorf("expected query shape to be %q; got %q", expected, s1)
+	}
+
+	if s3 := shape("mysql", "http://ottotech.com?name=rob&salary>=4000&page_size=5"); s3 != expected {
+		t.Errorf("expected query shape to be %q; got %q", expected, s3)
+	}
+
+	if s4 := shape("postgres", "http://ottotech.com?name=rob"); s4 == s1 {
+		t.Errorf("expected queries with different filters to have different shapes; got %q", s4)
+	}
+}
+
+func TestPaginator_Prepare(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, connector := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{
+			{int64(1), "rob", int64(5)},
+			{int64(2), "rob", int64(5)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	prepared, err := paginator.Prepare(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer prepared.Close()
+
+	for page := 1; page <= 3; page++ {
+		pag, err := prepared.Page(ctx, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for pag.NextData() {
+			e := Employee{}
+			if err = pag.Scan(&e); err != nil {
+				t.Fatal(err)
+			}
+			count++
+		}
+		if count != 2 {
+			t.Errorf("expected 2 records in page %d; got %d", page, count)
+		}
+		if res := pag.Response(); res.PageNumber != page || res.TotalSize != 5 {
+			t.Errorf("expected page %d with a total size of 5; got %+v", page, res)
+		}
+	}
+
+	expected := "SELECT id, name, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT $2 OFFSET $3"
+	queries := connector.executedQueries()
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 executed queries; got %d", len(queries))
+	}
+	for i, query := range queries {
+		if query != expected {
+			t.Errorf("expected sql command to be %q; got %q", expected, query)
+		}
+		wantArgs := fmt.Sprint([]driver.Value{"rob", int64(2), int64(i * 2)})
+		if gotArgs := fmt.Sprint(connector.args[i]); gotArgs != wantArgs {
+			t.Errorf("expected args of page %d to be %s; got %s", i+1, wantArgs, gotArgs)
+		}
+	}
+}
+
+func TestNewPaginator_OrderBy_Unknown_Column(t *testing.T) {
+	type Person struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opt := range []Option{
+		OrderByAsc("age"),
+		OrderByDesc("name; DROP TABLE person"),
+	} {
+		if _, err = NewPaginator(Person{}, "postgres", *u, opt); err == nil {
+			t.Error("expected an error when sorting by an unknown column")
+		}
+	}
+
+	paginator, err := NewPaginator(Person{}, "postgres", *u, OrderByDesc("name"), OrderByCase("name", "rob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM person ORDER BY name DESC,CASE WHEN name = $1 THEN 0 ELSE 1 END ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestPaginate_MariaDB_Without_Window_Function(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&page=2&page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "mariadb", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name FROM employee WHERE name = ? ORDER BY id LIMIT 2 OFFSET 2"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 1 || args[0] != "rob" {
+		t.Errorf("expected args to be [rob]; got %v", args)
+	}
+
+	db, connector := newFakeDB(
+		[]string{"id", "name"},
+		[][]driver.Value{{int64(3), "rob"}, {int64(4), "rob"}},
+	)
+	defer db.Close()
+	connector.count = 5
+
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	queries := connector.executedQueries()
+	expectedCount := "SELECT count(*) FROM employee WHERE name = ?"
+	if len(queries) != 2 || queries[0] != expectedCount || queries[1] != expected {
+		t.Errorf("expected the count query and the page query to be executed; got %v", queries)
+	}
+
+	ids := make([]int, 0)
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, e.ID)
+	}
+	if fmt.Sprint(ids) != "[3 4]" {
+		t.Errorf("expected ids to be [3 4]; got %v", ids)
+	}
+	res := paginator.Response()
+	if res.TotalSize != 5 || !res.HasNextPage || res.NextPageNumber != 3 {
+		t.Errorf("expected a total size of 5 with a next page; got %+v", res)
+	}
+}
+
+func TestPaginator_Count(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=rob&page=2&page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, connector := newFakeDB([]string{"id", "name"}, nil)
+	defer db.Close()
+	connector.count = 7
+
+	count, err := paginator.Count(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 7 {
+		t.Errorf("expected count to be 7; got %d", count)
+	}
+	queries := connector.executedQueries()
+	expected := "SELECT count(*) FROM employee WHERE name = $1"
+	if len(queries) != 1 || queries[0] != expected {
+		t.Errorf("expected only the count query %q to be executed; got %v", expected, queries)
+	}
+
+	// Count should not affect the state of the Paginator.
+	if res := paginator.Response(); res.TotalSize != 0 {
+		t.Errorf("expected the total size to be 0 before scanning the records; got %d", res.TotalSize)
+	}
+
+	if _, err = paginator.Count(context.Background(), nil); err == nil {
+		t.Error("expected an error with a nil querier")
+	}
+}
+
+func TestPaginator_KeysetPagination(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+		Age  int
+	}
+	u, err := url.Parse("http://ottotech.com?sort=-age,+name&page_size=2&page=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, KeysetPagination("cursor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, age, count(*) over() FROM employee ORDER BY age DESC,name ASC,id LIMIT 2 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	db, connector := newFakeDB(
+		[]string{"id", "name", "age", "count"},
+		[][]driver.Value{
+			{int64(2), "Ringo", int64(40), int64(5)},
+			{int64(4), "Rob", int64(30), int64(5)},
+		},
+	)
+	defer db.Close()
+
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res := paginator.Response()
+	if !res.HasNextPage || res.NextCursor == "" || res.NextPageNumber != 0 {
+		t.Fatalf("expected a next page with a continuation token; got %+v", res)
+	}
+
+	// Let's walk to the next page with the continuation token.
+	u, err = url.Parse("http://ottotech.com?sort=-age,+name&page_size=2&cursor=" + res.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, KeysetPagination("cursor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, age, count(*) over() FROM employee " +
+		"WHERE (age < $1 OR (age = $2 AND name > $3) OR (age = $4 AND name = $5 AND id > $6)) " +
+		"ORDER BY age DESC,name ASC,id LIMIT 2 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[30 30 Rob 30 Rob 4]" {
+		t.Errorf("expected args to be [30 30 Rob 30 Rob 4]; got %v", args)
+	}
+
+	connector.rows = [][]driver.Value{{int64(7), "Fred", int64(20), int64(1)}}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if res = paginator.Response(); res.HasNextPage || res.NextCursor != "" {
+		t.Errorf("expected no next page in the last page; got %+v", res)
+	}
+
+	// Invalid continuation tokens should be rejected, including the ones
+	// with values that are not scalars.
+	invalid := []string{
+		"abc",
+		encodeCursor([]interface{}{30}),
+		encodeCursor([]interface{}{map[string]interface{}{"a": 1}, "Rob"}),
+		encodeCursor([]interface{}{30, []interface{}{"Rob"}}),
+	}
+	for _, token := range invalid {
+		u, err = url.Parse("http://ottotech.com?sort=-age,+name&cursor=" + token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = NewPaginator(Employee{}, "postgres", *u, KeysetPagination("cursor")); err == nil {
+			t.Errorf("expected an error with the invalid continuation token %q", token)
+		}
+	}
+}
+
+func TestPaginator_KeysetPagination_Nullable_Sort(t *testing.T) {
+	type Employee struct {
+		ID    int     `paginate:"id"`
+		Name  string  `paginate:"filter"`
+		Bonus NullInt `paginate:"filter"`
+	}
+
+	tests := []struct {
+		dialect string
+		cursor  []interface{}
+		opts    []Option
+		where   string
+		args    string
+	}{
+		// Postgres sorts the NULL values last in ascending order.
+		{"postgres", []interface{}{"100", "4"}, nil, "WHERE ((bonus > $1 OR bonus IS NULL) OR (bonus = $2 AND id > $3))", "[100 100 4]"},
+		{"postgres", []interface{}{nil, "4"}, nil, "WHERE ((bonus IS NULL AND id > $1))", "[4]"},
+		// Mysql sorts the NULL values first in ascending order.
+		{"mysql", []interface{}{"100", "4"}, nil, "WHERE (bonus > ? OR (bonus = ? AND id > ?))", "[100 100 4]"},
+		{"mysql", []interface{}{nil, "4"}, nil, "WHERE (bonus IS NOT NULL OR (bonus IS NULL AND id > ?))", "[4]"},
+		// The explicit placements are respected.
+		{"postgres", []interface{}{nil, "4"}, []Option{NullsFirst("bonus")}, "WHERE (bonus IS NOT NULL OR (bonus IS NULL AND id > $1))", "[4]"},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?sort=+bonus&cursor=" + encodeCursor(tt.cursor))
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u, append([]Option{KeysetPagination("cursor")}, tt.opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(cmd, " "+tt.where+" ") {
+			t.Errorf("expected sql command %q to contain %q", cmd, tt.where)
+		}
+		if fmt.Sprint(args) != tt.args {
+			t.Errorf("expected args to be %s; got %v", tt.args, args)
+		}
+	}
+}
+
+func TestPaginate_SortParam(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+		Age  int
+	}
+	u, err := url.Parse("http://ottotech.com?order_by=+name,-age&sort=+age")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, SortParam("order_by"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, age, count(*) over() FROM employee ORDER BY name ASC,age DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// Malformed sort directives should be ignored.
+	u, err = url.Parse("http://ottotech.com?order_by=name,*age")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, SortParam("order_by"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, age, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	if _, err = NewPaginator(Employee{}, "postgres", *u, SortParam(" ")); err == nil {
+		t.Error("expected an error with an empty sort parameter")
+	}
+}
+
+func TestNewPaginator_Malformed_Pagination_Parameters(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+
+	tests := []struct {
+		rawURL   string
+		expected string
+		args     string
+	}{
+		{
+			rawURL:   "http://ottotech.com?page=2&page=5&page_size=10&page_size=3",
+			expected: "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 10 OFFSET 10",
+			args:     "[]",
+		},
+		{
+			rawURL:   "http://ottotech.com?page=2?name=ringo",
+			expected: "SELECT id, name, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 30",
+			args:     "[ringo]",
+		},
+		{
+			rawURL:   "http://ottotech.com?page=2&name=ringo&foo=%zz",
+			expected: "SELECT id, name, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 30",
+			args:     "[ringo]",
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// By default the query string will be normalized.
+		paginator, err := NewPaginator(Employee{}, "postgres", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command for %s to be %q; got %q", tt.rawURL, tt.expected, cmd)
+		}
+		if fmt.Sprint(args) != tt.args {
+			t.Errorf("expected args for %s to be %s; got %v", tt.rawURL, tt.args, args)
+		}
+
+		// With StrictPaging we should get an error instead.
+		if _, err = NewPaginator(Employee{}, "postgres", *u, StrictPaging()); err == nil {
+			t.Errorf("expected an error for %s with StrictPaging", tt.rawURL)
+		}
+	}
+
+	// Question marks that are not followed by a parameter are kept in the values.
+	for _, opts := range [][]Option{nil, {StrictPaging()}} {
+		u, err := url.Parse("http://ottotech.com?name=why?&page=2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(args) != "[why?]" {
+			t.Errorf("expected args to be [why?]; got %v", args)
+		}
+	}
+}
+
+func ExampleGroupBy() {
+	type Department struct {
+		Name string `paginate:"id;col=department"`
+	}
+	u, err := url.Parse("http://ottotech.com?page=2&page_size=5")
+	if err != nil {
+		log.Fatal(err)
+	}
+	paginator, err := NewPaginator(Department{}, "postgres", *u, TableName("employees"), GroupBy("department"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sql, _, err := paginator.Paginate()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(sql)
+	// Output:
+	// SELECT department, count(*) over() FROM employees GROUP BY department ORDER BY department LIMIT 5 OFFSET 5
+}
+
+func TestPaginate_GroupBy(t *testing.T) {
+	type Employee struct {
+		ID         int `paginate:"id"`
+		Department string
+		Salary     float64 `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?salary>5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "mariadb", *u, GroupBy("id", "department"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, department, salary FROM employee WHERE salary > ? GROUP BY id, department ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// The total size should be the number of groups.
+	db, connector := newFakeDB([]string{"id", "department", "salary"}, [][]driver.Value{})
+	defer db.Close()
+	connector.count = 3
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	queries := connector.executedQueries()
+	expectedCount := "SELECT count(*) FROM (SELECT 1 FROM employee WHERE salary > ? GROUP BY id, department) AS grouped"
+	if len(queries) == 0 || queries[0] != expectedCount {
+		t.Errorf("expected the count query %q to be executed; got %v", expectedCount, queries)
+	}
+	if res := paginator.Response(); res.TotalSize != 3 {
+		t.Errorf("expected a total size of 3; got %+v", res)
+	}
+
+	if _, err = NewPaginator(Employee{}, "mariadb", *u, GroupBy("department; DROP TABLE employee")); err == nil {
+		t.Error("expected an error when grouping by an unknown column")
+	}
+}
+
+func TestPaginator_Remaining_And_Consumed(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	type Other struct {
+		ID   int
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{
+			{int64(1), "Ringo", int64(3)},
+			{int64(2), "Bill", int64(3)},
+			{int64(3), "Mark", int64(3)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	if paginator.Remaining() != 3 || paginator.Consumed() != 0 {
+		t.Errorf("expected 3 remaining and 0 consumed rows before scanning; got %d and %d",
+			paginator.Remaining(), paginator.Consumed())
+	}
+
+	// The second call to Scan fails with a wrong destination, which
+	// stops the iteration with a row that has not been scanned.
+	i := 0
+	for paginator.NextData() {
+		if i == 0 {
+			err = paginator.Scan(&Employee{})
+		} else {
+			err = paginator.Scan(&Other{})
+		}
+		i++
+	}
+	if err == nil {
+		t.Fatal("expected an error when scanning into a wrong destination")
+	}
+	if paginator.Remaining() != 2 || paginator.Consumed() != 1 {
+		t.Errorf("expected 2 remaining and 1 consumed rows after the scan error; got %d and %d",
+			paginator.Remaining(), paginator.Consumed())
+	}
+
+	paginator.Reset()
+	if paginator.Remaining() != 0 || paginator.Consumed() != 0 {
+		t.Errorf("expected no remaining and no consumed rows after Reset; got %d and %d",
+			paginator.Remaining(), paginator.Consumed())
+	}
+}
+
+func TestPaginator_Concurrent_Scan(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make([][]driver.Value, 0)
+	for i := 1; i <= 30; i++ {
+		values = append(values, []driver.Value{int64(i), fmt.Sprintf("employee %d", i), int64(30)})
+	}
+	db, _ := newFakeDB([]string{"id", "name", "count"}, values)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run with -race to detect unguarded state transitions.
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[int]int)
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for paginator.NextData() {
+				e := Employee{}
+				if err := paginator.Scan(&e); err != nil {
+					if !errors.Is(err, ErrPaginatorIsClosed) {
+						t.Error(err)
+					}
+					return
+				}
+				mu.Lock()
+				seen[e.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	// The response can be read while the rows are scanned.
+	for g := 0; g < 2; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if res := paginator.Response(); res.TotalSize != 30 {
+					t.Errorf("expected a total size of 30; got %d", res.TotalSize)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 30 {
+		t.Errorf("expected 30 scanned employees; got %d", len(seen))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("expected the employee %d to be scanned once; got %d times", id, n)
+		}
+	}
+	if paginator.Consumed() != 30 || paginator.Remaining() != 0 {
+		t.Errorf("expected 30 consumed and no remaining rows; got %d and %d",
+			paginator.Consumed(), paginator.Remaining())
+	}
+}
+
+func TestPaginator_ScanMap(t *testing.T) {
+	type Employee struct {
+		ID         int     `paginate:"id"`
+		FirstName  string  `paginate:"col=name"`
+		Manager    *string `json:"boss"`
+		NullSalary NullFloat64
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "name", "manager", "null_salary", "count"},
+		[][]driver.Value{
+			{int64(1), "Ringo", nil, 5400.0, int64(2)},
+			{int64(2), nil, "Ringo", nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	// The keys should be the column names, NULL values should be nil.
+	expected := []map[string]interface{}{
+		{"id": 1, "name": "Ringo", "manager": nil, "null_salary": 5400.0},
+		{"id": 2, "name": nil, "manager": "Ringo", "null_salary": nil},
+	}
+	maps := make([]map[string]interface{}, 0)
+	for paginator.NextData() {
+		m, err := paginator.ScanMap()
+		if err != nil {
+			t.Fatal(err)
+		}
+		maps = append(maps, m)
+	}
+	if !reflect.DeepEqual(maps, expected) {
+		t.Errorf("expected maps to be %#v; got %#v", expected, maps)
+	}
+	if res := paginator.Response(); res.PageCount != 2 || res.TotalSize != 2 {
+		t.Errorf("expected a page count and total size of 2; got %+v", res)
+	}
+
+	if _, err = paginator.ScanMap(); !errors.Is(err, ErrPaginatorIsClosed) {
+		t.Errorf("expected ErrPaginatorIsClosed after scanning all the rows; got %v", err)
+	}
+}
+
+func TestPaginator_ScanRows(t *testing.T) {
+	type Employee struct {
+		ID      int `paginate:"id"`
+		Name    string
+		Manager *string
+		Salary  float64
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "name", "manager", "salary", "count"},
+		[][]driver.Value{
+			{int64(1), "Ringo", nil, 5400.0, int64(2)},
+			{int64(2), nil, "Ringo", nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := paginator.ScanRows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{
+		{1, "Ringo", nil, 5400.0},
+		{2, nil, "Ringo", nil},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows; got %d", len(expected), len(rows))
+	}
+	for i := range rows {
+		if !reflect.DeepEqual(rows[i], expected[i]) {
+			t.Errorf("expected row %d to be %#v; got %#v", i, expected[i], rows[i])
+		}
+	}
+	if res := paginator.Response(); res.PageCount != 2 || res.TotalSize != 2 {
+		t.Errorf("expected a page count and total size of 2; got %+v", res)
+	}
+	if paginator.Remaining() != 0 || paginator.Consumed() != 2 {
+		t.Errorf("expected no remaining and 2 consumed rows; got %d and %d",
+			paginator.Remaining(), paginator.Consumed())
+	}
+
+	if paginator.NextData() {
+		t.Error("expected no data left after ScanRows")
+	}
+	if _, err = paginator.ScanRows(); err != ErrPaginatorIsClosed {
+		t.Errorf("expected ErrPaginatorIsClosed; got %v", err)
+	}
+}
+
+func TestPaginate_AddHavingClause(t *testing.T) {
+	type Department struct {
+		Name   string  `paginate:"id;col=department"`
+		Salary float64 `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?salary>5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Department{}, "postgres", *u, TableName("employees"), GroupBy("department", "salary"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := NewRawHavingClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.AddPredicate("count(*) > ? AND max(salary) < ?")
+	raw.AddArg(5)
+	raw.AddArg(9000)
+	if err = paginator.AddHavingClause(raw); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT department, salary, count(*) over() FROM employees WHERE salary > $1 " +
+		"GROUP BY department, salary HAVING count(*) > $2 AND max(salary) < $3 ORDER BY department LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[5000 5 9000]" {
+		t.Errorf("expected args to be [5000 5 9000]; got %v", args)
+	}
+
+	// The count query used by mariadb should also filter the groups.
+	paginator, err = NewPaginator(Department{}, "mariadb", *u, TableName("employees"), GroupBy("department", "salary"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.dialect = "mariadb"
+	if err = paginator.AddHavingClause(raw); err != nil {
+		t.Fatal(err)
+	}
+	db, connector := newFakeDB([]string{"department", "salary"}, [][]driver.Value{})
+	defer db.Close()
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT count(*) FROM (SELECT 1 FROM employees WHERE salary > ? " +
+		"GROUP BY department, salary HAVING count(*) > ? AND max(salary) < ?) AS grouped"
+	if queries := connector.executedQueries(); len(queries) == 0 || queries[0] != expected {
+		t.Errorf("expected the count query %q to be executed; got %v", expected, queries)
+	}
+	if fmt.Sprint(connector.args[0]) != "[5000 5 9000]" {
+		t.Errorf("expected the count query args to be [5000 5 9000]; got %v", connector.args[0])
+	}
+
+	raw.AddArg(1)
+	if err = paginator.AddHavingClause(raw); err == nil {
+		t.Error("expected an error when the number of placeholders and arguments differ")
+	}
+
+	raw, err = NewRawHavingClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.AddPredicate("count(*) > 5")
+	if err = paginator.AddHavingClause(raw); err == nil {
+		t.Error("expected an error when the dialects of the having clause and the paginator differ")
+	}
+
+	// Several having clauses should be wrapped in parentheses, so the OR
+	// of the first one does not bypass the second one.
+	paginator, err = NewPaginator(Department{}, "postgres", *u, TableName("employees"), GroupBy("department", "salary"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, predicate := range []string{"count(*) > ? OR sum(salary) > ?", "avg(salary) < ?"} {
+		raw, err = NewRawHavingClause("postgres")
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw.AddPredicate(predicate)
+		for i := 0; i < strings.Count(predicate, "?"); i++ {
+			raw.AddArg(i + 1)
+		}
+		if err = paginator.AddHavingClause(raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT department, salary, count(*) over() FROM employees WHERE salary > $1 " +
+		"GROUP BY department, salary HAVING (count(*) > $2 OR sum(salary) > $3) AND (avg(salary) < $4) ORDER BY department LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestPaginator_Count_Column(t *testing.T) {
+	type Employee struct {
+		ID      int `paginate:"id"`
+		Name    string
+		Manager *string
+		Salary  float64
+		Active  bool
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dialect := range []string{"postgres", "mariadb"} {
+		columns := []string{"id", "name", "manager", "salary", "active"}
+		rows := [][]driver.Value{
+			{int64(1), "Ringo", nil, 5400.0, true},
+			{int64(2), "John", "Ringo", 4650.9, false},
+		}
+		if dialect == "postgres" {
+			columns = append(columns, "count")
+			for i := range rows {
+				rows[i] = append(rows[i], int64(7))
+			}
+		}
+		db, connector := newFakeDB(columns, rows)
+		connector.count = 7
+
+		paginator, err := NewPaginator(Employee{}, dialect, *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = paginator.Execute(context.Background(), db); err != nil {
+			t.Fatal(err)
+		}
+
+		results := make([]Employee, 0)
+		for paginator.NextData() {
+			e := Employee{}
+			if err = paginator.Scan(&e); err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, e)
+		}
+		if len(results) != 2 || results[1].Name != "John" || *results[1].Manager != "Ringo" || !results[0].Active {
+			t.Errorf("%s: expected the fields to be scanned correctly; got %+v", dialect, results)
+		}
+		if res := paginator.Response(); res.TotalSize != 7 || res.PageCount != 2 {
+			t.Errorf("%s: expected a total size of 7 and a page count of 2; got %+v", dialect, res)
+		}
+		db.Close()
+	}
+}
+
+func TestPaginate_Distinct(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		TenantID int    `paginate:"col=tenant_id"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=ringo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{
+			dialect: "postgres",
+			expected: "SELECT DISTINCT employees.id, employees.name, employees.tenant_id, count(*) over() FROM employees " +
+				"LEFT JOIN manager ON employees.tenant_id = manager.tenant_id WHERE employees.name = $1 " +
+				"GROUP BY employees.id, employees.name, employees.tenant_id ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			dialect: "mariadb",
+			expected: "SELECT DISTINCT employees.id, employees.name, employees.tenant_id FROM employees " +
+				"LEFT JOIN manager ON employees.tenant_id = manager.tenant_id WHERE employees.name = ? " +
+				"GROUP BY employees.id, employees.name, employees.tenant_id ORDER BY id LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u, TableName("employees"), Distinct())
+		if err != nil {
+			t.Fatal(err)
+		}
+		join, err := NewLeftJoinClause(tt.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		join.On("tenant_id", "manager", "tenant_id")
+		if err = paginator.AddJoinClause(join); err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command to be %q; got %q", tt.expected, cmd)
+		}
+	}
+}
+
+func TestPaginate_AddConditionalJoinClause(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	developer, err := NewLeftJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	developer.On("id", "developer", "employee_id")
+	manager, err := NewLeftJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.On("id", "manager", "employee_id")
+	for _, join := range []LeftJoin{developer, manager} {
+		if err = paginator.AddConditionalJoinClause(join); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No clause references the joined tables yet.
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM employees ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	raw, err := NewRawWhereClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.Eq("manager.tenant_id", 1)
+	if err = paginator.AddWhereClause(raw); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT employees.id, employees.name, count(*) over() FROM employees " +
+		"LEFT JOIN manager ON employees.id = manager.employee_id WHERE (manager.tenant_id = $1) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestPaginate_AddConditionalJoinClause_Request_References(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		Language string `paginate:"filter;noselect;col=developer.programming_language;param=lang"`
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "name=Ringo",
+			expected: "SELECT id, name, count(*) over() FROM employees WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query: "lang=go",
+			expected: "SELECT employees.id, employees.name, count(*) over() FROM employees " +
+				"LEFT JOIN developer ON employees.id = developer.employee_id WHERE developer.programming_language = $1 ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query: "sort=-developer.programming_language",
+			expected: "SELECT employees.id, employees.name, count(*) over() FROM employees " +
+				"LEFT JOIN developer ON employees.id = developer.employee_id ORDER BY developer.programming_language DESC,id LIMIT 30 OFFSET 0",
+		},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		developer, err := NewLeftJoinClause("postgres")
+		if err != nil {
+			t.Fatal(err)
+		}
+		developer.On("id", "developer", "employee_id")
+		if err = paginator.AddConditionalJoinClause(developer); err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("%s: expected sql command to be %q; got %q", tt.query, tt.expected, cmd)
+		}
+	}
+}
+
+func TestPaginator_Columns(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string
+		Salary   float64 `paginate:"filter"`
+		Manager  *string
+	}
+	u, err := url.Parse("http://ottotech.com?salary>5000&sort=-salary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, Columns("Name", "last_name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, last_name, count(*) over() FROM employee WHERE salary > $1 ORDER BY salary DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	db, _ := newFakeDB(
+		[]string{"id", "name", "last_name", "count"},
+		[][]driver.Value{{int64(1), "Ringo", "Star", int64(1)}},
+	)
+	defer db.Close()
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		if e.ID != 1 || e.Name != "Ringo" || e.LastName != "Star" {
+			t.Errorf("expected the selected fields to be scanned; got %+v", e)
+		}
+		if e.Salary != 0 || e.Manager != nil {
+			t.Errorf("expected the fields that are not selected to be zero; got %+v", e)
+		}
+	}
+	if res := paginator.Response(); res.TotalSize != 1 {
+		t.Errorf("expected a total size of 1; got %+v", res)
+	}
+
+	if _, err = NewPaginator(Employee{}, "postgres", *u, Columns("Unknown")); err == nil {
+		t.Error("expected an error when selecting an unknown field")
+	}
+}
+
+func TestPaginator_Response_After_Scan_Failure(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com?page=3&page_size=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second row cannot be scanned into the int id.
+	db, _ := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{
+			{int64(1), "Ringo", int64(20)},
+			{"abc", "John", int64(20)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err == nil {
+		t.Fatal("expected an error when scanning an invalid id")
+	}
+
+	res := paginator.Response()
+	if res.PageNumber != 3 || res.PageSize != 5 {
+		t.Errorf("expected the requested page 3 with a page size of 5; got %+v", res)
+	}
+	if !res.Partial {
+		t.Errorf("expected the response to be partial; got %+v", res)
+	}
+	// The total size of the first row, which was scanned, should not be reported.
+	if res.TotalSize != 0 || res.PageCount != 0 || res.TotalPages != 0 || res.HasNextPage || res.NextPageNumber != 0 {
+		t.Errorf("expected the counts of the partial response to be zero; got %+v", res)
+	}
+
+	// A successful execution should not be partial.
+	db, _ = newFakeDB([]string{"id", "name", "count"}, [][]driver.Value{{int64(1), "Ringo", int64(1)}})
+	defer db.Close()
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	if res = paginator.Response(); res.Partial || res.PageSize != 5 {
+		t.Errorf("expected a complete response with a page size of 5; got %+v", res)
+	}
+}
+
+func TestPaginator_Scan_Bytes(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Data []byte `paginate:"col=data"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "data", "count"},
+		[][]driver.Value{
+			{int64(1), []byte{0xde, 0xad}, int64(2)},
+			{int64(2), nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	// NULL values should leave the slices nil.
+	expected := []Employee{{ID: 1, Data: []byte{0xde, 0xad}}, {ID: 2}}
+	employees := make([]Employee, 0)
+	for paginator.NextData() {
+		employee := Employee{}
+		if err = paginator.Scan(&employee); err != nil {
+			t.Fatal(err)
+		}
+		employees = append(employees, employee)
+	}
+	if !reflect.DeepEqual(employees, expected) {
+		t.Errorf("expected employees to be %#v; got %#v", expected, employees)
+	}
+}
+
+func TestPaginator_Scan_NullInt64_And_NullInt32(t *testing.T) {
+	type Employee struct {
+		ID     int       `paginate:"id"`
+		Big    NullInt64 `paginate:"col=big"`
+		Medium NullInt32 `paginate:"col=medium"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "big", "medium", "count"},
+		[][]driver.Value{
+			{int64(1), int64(1) << 40, int64(-7), int64(2)},
+			{int64(2), nil, nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Employee{
+		{ID: 1, Big: NullInt64{Int64: 1 << 40, Valid: true}, Medium: NullInt32{Int32: -7, Valid: true}},
+		{ID: 2},
+	}
+	employees := make([]Employee, 0)
+	for paginator.NextData() {
+		employee := Employee{}
+		if err = paginator.Scan(&employee); err != nil {
+			t.Fatal(err)
+		}
+		employees = append(employees, employee)
+	}
+	if !reflect.DeepEqual(employees, expected) {
+		t.Errorf("expected employees to be %+v; got %+v", expected, employees)
+	}
+
+	// The values should round-trip through JSON without losing their width.
+	b, err := json.Marshal(employees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON := `[{"ID":1,"Big":1099511627776,"Medium":-7},{"ID":2,"Big":null,"Medium":null}]`
+	if string(b) != expectedJSON {
+		t.Errorf("expected json to be %s; got %s", expectedJSON, b)
+	}
+	decoded := make([]Employee, 0)
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("expected decoded employees to be %+v; got %+v", expected, decoded)
+	}
+
+	// Values that do not fit in an int32 should not be scanned into NullInt32.
+	var n NullInt32
+	if err = n.Scan(int64(1) << 40); err == nil {
+		t.Errorf("expected an error scanning a value that overflows NullInt32; got %+v", n)
+	}
+}
+
+func TestPaginator_Scan_NullDecimal_And_NullFloat32(t *testing.T) {
+	type Employee struct {
+		ID     int         `paginate:"id"`
+		Bonus  NullDecimal `paginate:"col=bonus"`
+		Rating NullFloat32 `paginate:"col=rating"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The drivers return NUMERIC(10,2) columns as raw bytes.
+	db, _ := newFakeDB(
+		[]string{"id", "bonus", "rating", "count"},
+		[][]driver.Value{
+			{int64(1), []byte("12345678.91"), 4.5, int64(2)},
+			{int64(2), nil, nil, int64(2)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Employee{
+		{ID: 1, Bonus: NullDecimal{Decimal: "12345678.91", Valid: true}, Rating: NullFloat32{Float32: 4.5, Valid: true}},
+		{ID: 2},
+	}
+	employees := make([]Employee, 0)
+	for paginator.NextData() {
+		employee := Employee{}
+		if err = paginator.Scan(&employee); err != nil {
+			t.Fatal(err)
+		}
+		employees = append(employees, employee)
+	}
+	if !reflect.DeepEqual(employees, expected) {
+		t.Errorf("expected employees to be %+v; got %+v", expected, employees)
+	}
+
+	// The decimals should be marshaled to unquoted json numbers.
+	b, err := json.Marshal(employees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON := `[{"ID":1,"Bonus":12345678.91,"Rating":4.5},{"ID":2,"Bonus":null,"Rating":null}]`
+	if string(b) != expectedJSON {
+		t.Errorf("expected json to be %s; got %s", expectedJSON, b)
+	}
+	decoded := make([]Employee, 0)
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("expected decoded employees to be %+v; got %+v", expected, decoded)
+	}
+
+	var n NullDecimal
+	if err = json.Unmarshal([]byte(`"0.10"`), &n); err != nil || n.Decimal != "0.10" {
+		t.Errorf("expected a quoted decimal to be accepted; got %+v, %v", n, err)
+	}
+	if err = json.Unmarshal([]byte(`"ten"`), &n); err == nil {
+		t.Errorf("expected an error unmarshaling an invalid decimal; got %+v", n)
+	}
+}
+
+func TestNullables_Scan_Raw_Bytes(t *testing.T) {
+	var nf NullFloat64
+	if err := nf.Scan([]uint8("4250.75")); err != nil {
+		t.Fatal(err)
+	}
+	if !nf.Valid || nf.Float64 != 4250.75 {
+		t.Errorf("expected NullFloat64 to be 4250.75; got %+v", nf)
+	}
+	if err := nf.Scan([]uint8("abc")); err == nil {
+		t.Errorf("expected an error scanning an invalid float; got %+v", nf)
+	}
+
+	var nt NullTime
+	if err := nt.Scan([]uint8("2020-03-01 10:30:00")); err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Date(2020, 3, 1, 10, 30, 0, 0, time.UTC)
+	if !nt.Valid || !nt.Time.Equal(expected) {
+		t.Errorf("expected NullTime to be %v; got %+v", expected, nt)
+	}
+	if err := nt.Scan([]uint8("2020-03-01 10:30:00.123456")); err != nil || nt.Time.Nanosecond() != 123456000 {
+		t.Errorf("expected NullTime to keep the fractional seconds; got %+v, %v", nt, err)
+	}
+	if err := nt.Scan([]uint8("yesterday")); err == nil {
+		t.Errorf("expected an error scanning an invalid timestamp; got %+v", nt)
+	}
+}
+
+func TestPaginator_ScanMaps_Uses_JSON_Tags(t *testing.T) {
+	type Employee struct {
+		ID       int     `json:"employee_id" paginate:"id;col=id"`
+		Name     string  `json:"first_name,omitempty" paginate:"col=name"`
+		LastName string  `paginate:"col=last_name"`
+		Salary   float64 `json:"-" paginate:"col=salary"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "name", "last_name", "salary", "count"},
+		[][]driver.Value{{int64(1), "Ringo", "Star", 5400.0, int64(1)}},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	maps, err := paginator.ScanMaps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{"employee_id": 1, "first_name": "Ringo", "last_name": "Star"},
+	}
+	if !reflect.DeepEqual(maps, expected) {
+		t.Errorf("expected maps to be %v; got %v", expected, maps)
+	}
+}
+
+func TestPaginator_ExcludeColumns(t *testing.T) {
+	type User struct {
+		ID           int    `paginate:"id"`
+		Name         string `paginate:"filter"`
+		PasswordHash string `paginate:"filter;col=password_hash"`
+	}
+	u, err := url.Parse("http://ottotech.com?password_hash=abc&sort=-password_hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(User{}, "postgres", *u, ExcludeColumns("PasswordHash"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM user ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 0 {
+		t.Errorf("expected no args; got %v", args)
+	}
+
+	db, _ := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{{int64(1), "Ringo", int64(1)}},
+	)
+	defer db.Close()
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	for paginator.NextData() {
+		user := User{}
+		if err = paginator.Scan(&user); err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != "Ringo" || user.PasswordHash != "" {
+			t.Errorf("expected the excluded field to be zero; got %+v", user)
+		}
+	}
+
+	for _, name := range []string{"ID", "id"} {
+		if _, err = NewPaginator(User{}, "postgres", *u, ExcludeColumns(name)); err == nil {
+			t.Errorf("expected an error when excluding the id with %q", name)
+		}
+	}
+}
+
+func TestPaginator_Execute_ConcurrentCount(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com?page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, connector := newFakeDB(
+		[]string{"id", "name"},
+		[][]driver.Value{{int64(1), "Ringo"}, {int64(2), "John"}},
+	)
+	defer db.Close()
+	connector.count = 5
+
+	paginator, err := NewPaginator(Employee{}, "mariadb", *u, ConcurrentCount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	if queries := connector.executedQueries(); len(queries) != 2 {
+		t.Errorf("expected the count query and the page query to be executed; got %v", queries)
+	}
+	names := make([]string, 0)
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, e.Name)
+	}
+	if fmt.Sprint(names) != "[Ringo John]" {
+		t.Errorf("expected names to be [Ringo John]; got %v", names)
+	}
+	if res := paginator.Response(); res.TotalSize != 5 || !res.HasNextPage || res.PageCount != 2 {
+		t.Errorf("expected a total size of 5 with a next page; got %+v", res)
+	}
+
+	// A failure of the count query should cancel the page query.
+	countErr := errors.New("count failed")
+	connector.countErr = countErr
+	connector.blockRows = true
+	paginator, err = NewPaginator(Employee{}, "mariadb", *u, ConcurrentCount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = paginator.Execute(ctx, db); err != countErr {
+		t.Errorf("expected the error of the count query; got %v", err)
+	}
+	connector.mu.Lock()
+	cancelled := connector.cancelled
+	connector.mu.Unlock()
+	if cancelled != context.Canceled {
+		t.Errorf("expected the page query to be cancelled; got %v", cancelled)
+	}
+
+	// A failure of the page query should cancel the count query, which blocks
+	// until then, and its error should not be hidden by the cancellation.
+	rowsErr := errors.New("syntax error")
+	connector.countErr = nil
+	connector.blockRows = false
+	connector.blockCount = true
+	connector.rowsErr = rowsErr
+	paginator, err = NewPaginator(Employee{}, "mariadb", *u, ConcurrentCount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(ctx, db); err != rowsErr {
+		t.Errorf("expected the error of the page query; got %v", err)
+	}
+}
+
+func TestPaginate_RightJoin(t *testing.T) {
+	type Employee struct {
+		ID                  int    `paginate:"id"`
+		ProgrammingLanguage string `paginate:"col=developer.programming_language"`
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "mysql", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rightJoin, err := NewRightJoinClause("mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rightJoin.On("id", "developer", "employee_id")
+	if err = paginator.AddJoinClause(rightJoin); err != nil {
+		t.Fatal(err)
+	}
+	sql, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSQL := "SELECT employees.id, developer.programming_language, count(*) over() FROM employees " +
+		"RIGHT JOIN developer ON employees.id = developer.employee_id ORDER BY id LIMIT 30 OFFSET 0"
+	if sql != expectedSQL {
+		t.Errorf("expected sql %q; got %q", expectedSQL, sql)
+	}
+
+	if _, err = NewRightJoinClause("sqlite"); err == nil {
+		t.Error("expected an error with an unsupported dialect")
+	}
+}
+
+func TestPaginate_Join_Select(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		Language string `paginate:"filter;param=lg"`
+	}
+	u, err := url.Parse("http://ottotech.com?lg=Go&sort=-language")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	join, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "developer", "employee_id").Select(map[string]string{"Language": "programming_language"})
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT employees.id, employees.name, developer.programming_language, count(*) over() FROM employees " +
+		"JOIN developer ON employees.id = developer.employee_id WHERE developer.programming_language = $1 " +
+		"ORDER BY developer.programming_language DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[Go]" {
+		t.Errorf("expected args to be [Go]; got %v", args)
+	}
+
+	// Other paginators of the same table should not be affected.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, language, count(*) over() FROM employees WHERE language = $1 ORDER BY language DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// The columns given in the options are renamed too.
+	u, err = url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, TableName("employees"),
+		OrderByCase("language", "Go"), DefaultOrderBy("language", "DESC"), GroupBy("id", "name", "language"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	join, err = NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "developer", "employee_id").Select(map[string]string{"Language": "programming_language"})
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT employees.id, employees.name, developer.programming_language, count(*) over() FROM employees " +
+		"JOIN developer ON employees.id = developer.employee_id " +
+		"GROUP BY employees.id, employees.name, developer.programming_language " +
+		"ORDER BY CASE WHEN developer.programming_language = $1 THEN 0 ELSE 1 END ASC,developer.programming_language DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	invalid := []struct {
+		selections  map[string]string
+		conditional bool
+		opts        []Option
+	}{
+		{map[string]string{"Unknown": "programming_language"}, false, nil},
+		{map[string]string{"ID": "employee_id"}, false, nil},
+		{map[string]string{"Language": "programming_language; DROP"}, false, nil},
+		{map[string]string{"Language": "programming_language"}, true, nil},
+		{map[string]string{"Language": "programming_language"}, false, []Option{Distinct()}},
+		{map[string]string{"Language": "programming_language"}, false, []Option{Search("q", "language")}},
+		{map[string]string{"Language": "programming_language"}, false, []Option{OrderByAsc("language"), KeysetPagination("cursor")}},
+	}
+	for _, tt := range invalid {
+		paginator, err = NewPaginator(Employee{}, "postgres", *u, append([]Option{TableName("employees")}, tt.opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		join, err := NewLeftJoinClause("postgres")
+		if err != nil {
+			t.Fatal(err)
+		}
+		join.On("id", "developer", "employee_id").Select(tt.selections)
+		if tt.conditional {
+			err = paginator.AddConditionalJoinClause(join)
+		} else {
+			err = paginator.AddJoinClause(join)
+		}
+		if err == nil {
+			t.Errorf("expected an error selecting %v (conditional: %v)", tt.selections, tt.conditional)
+		}
+	}
+}
+
+func TestPaginate_Join_Filters_Clashing_Columns(t *testing.T) {
+	type Employee struct {
+		ID              int `paginate:"id"`
+		TenantID        int `paginate:"filter;col=tenant_id"`
+		ManagerTenantID int `paginate:"filter;param=manager_tenant"`
+	}
+	u, err := url.Parse("http://ottotech.com?tenant_id=1&manager_tenant=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	join, err := NewInnerJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	join.On("id", "manager", "employee_id").Select(map[string]string{"ManagerTenantID": "tenant_id"})
+	if err = paginator.AddJoinClause(join); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both tables have a tenant_id column, so the filtered columns
+	// should be qualified with the names of their tables.
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT employees.id, employees.tenant_id, manager.tenant_id, count(*) over() FROM employees " +
+		"JOIN manager ON employees.id = manager.employee_id WHERE employees.tenant_id = $1 AND manager.tenant_id = $2 " +
+		"ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[1 2]" {
+		t.Errorf("expected args to be [1 2]; got %v", args)
+	}
+}
+
+func TestPaginate_RawJoinClause(t *testing.T) {
+	type Employee struct {
+		ID                  int    `paginate:"id"`
+		Name                string `paginate:"filter"`
+		ProgrammingLanguage string `paginate:"col=lang.programming_language"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=ringo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lateral := "JOIN LATERAL (SELECT programming_language FROM developer " +
+		"WHERE developer.employee_id = employees.id AND programming_language = ?) AS lang ON true"
+
+	tests := []struct {
+		dialect     string
+		expectedSQL string
+	}{
+		{
+			dialect: "postgres",
+			expectedSQL: "SELECT employees.id, employees.name, lang.programming_language, count(*) over() FROM employees " +
+				"JOIN LATERAL (SELECT programming_language FROM developer WHERE developer.employee_id = employees.id " +
+				"AND programming_language = $1) AS lang ON true WHERE employees.name = $2 ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			dialect: "mysql",
+			expectedSQL: "SELECT employees.id, employees.name, lang.programming_language, count(*) over() FROM employees " +
+				"JOIN LATERAL (SELECT programming_language FROM developer WHERE developer.employee_id = employees.id " +
+				"AND programming_language = ?) AS lang ON true WHERE employees.name = ? ORDER BY id LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rawJoin, err := NewRawJoinClause(tt.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rawJoin.AddRaw(lateral)
+		rawJoin.AddArg("Go")
+		if err = paginator.AddJoinClause(rawJoin); err != nil {
+			t.Fatal(err)
+		}
+		sql, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != tt.expectedSQL {
+			t.Errorf("expected sql %q; got %q", tt.expectedSQL, sql)
+		}
+		// The arguments of the join clause should come before the
+		// arguments of the where clause.
+		expectedArgs := []interface{}{"Go", "ringo"}
+		if !reflect.DeepEqual(args, expectedArgs) {
+			t.Errorf("expected args %v; got %v", expectedArgs, args)
+		}
+	}
+
+	invalid := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{sql: "WHERE 1 = 1"},
+		{sql: "CROSS JOIN developer; DROP TABLE employees"},
+		{sql: "JOIN LATERAL (SELECT 1 WHERE ? = ?) AS x ON true", args: []interface{}{1}},
+		{sql: "CROSS JOIN developer", args: []interface{}{1}},
+	}
+	for _, tt := range invalid {
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rawJoin, err := NewRawJoinClause("postgres")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rawJoin.AddRaw(tt.sql)
+		for _, arg := range tt.args {
+			rawJoin.AddArg(arg)
+		}
+		if err = paginator.AddJoinClause(rawJoin); err == nil {
+			t.Errorf("expected an error with the raw join clause %q and args %v", tt.sql, tt.args)
+		}
+	}
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, TableName("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawJoin, err := NewRawJoinClause("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawJoin.AddRaw("CROSS JOIN developer")
+	if err = paginator.AddConditionalJoinClause(rawJoin); err == nil {
+		t.Error("expected an error with a conditional raw join clause")
+	}
+}
+
+func TestPaginate_FieldsParam(t *testing.T) {
+	type Employee struct {
+		ID       int `paginate:"id"`
+		Name     string
+		LastName string
+		Notes    string
+		Metadata string
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "fields=-notes,-Metadata",
+			expected: "SELECT id, name, last_name, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "fields=name,%2Blast_name",
+			expected: "SELECT id, name, last_name, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "fields=-id,-name",
+			expected: "SELECT id, last_name, notes, metadata, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "page=1",
+			expected: "SELECT id, name, last_name, notes, metadata, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, FieldsParam("fields"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command for %s to be %q; got %q", tt.query, tt.expected, cmd)
+		}
+	}
+
+	for _, query := range []string{"fields=name,-notes", "fields=-unknown"} {
+		u, err := url.Parse("http://ottotech.com?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = NewPaginator(Employee{}, "postgres", *u, FieldsParam("fields")); err == nil {
+			t.Errorf("expected an error for %s", query)
+		}
+	}
+}
+
+func TestPaginate_AllowSparseFields(t *testing.T) {
+	type Employee struct {
+		ID       int `paginate:"id"`
+		Name     string
+		LastName string
+		Notes    string
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "fields=id,name",
+			expected: "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "fields=name,unknown",
+			expected: "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "fields=unknown",
+			expected: "SELECT id, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "fields=-notes,-unknown",
+			expected: "SELECT id, name, last_name, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+		{
+			query:    "page=1",
+			expected: "SELECT id, name, last_name, notes, count(*) over() FROM employee ORDER BY id LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, AllowSparseFields())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command for %s to be %q; got %q", tt.query, tt.expected, cmd)
+		}
+	}
+}
+
+func TestPaginate_OrderBy_With_Nulls_Placement(t *testing.T) {
+	type Employee struct {
+		ID     int `paginate:"id"`
+		Salary NullFloat64
+		Bonus  NullFloat64
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{
+			dialect: "postgres",
+			expected: "SELECT id, salary, bonus, count(*) over() FROM employee " +
+				"ORDER BY salary DESC NULLS LAST,bonus ASC NULLS FIRST,id LIMIT 30 OFFSET 0",
+		},
+		{
+			dialect: "mysql",
+			expected: "SELECT id, salary, bonus, count(*) over() FROM employee " +
+				"ORDER BY salary IS NULL ASC,salary DESC,bonus IS NULL DESC,bonus ASC,id LIMIT 30 OFFSET 0",
+		},
+	}
+
+	for _, tt := range tests {
+		paginator, err := NewPaginator(Employee{}, tt.dialect, *u, OrderByDescNullsLast("salary"), OrderByAscNullsFirst("bonus"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command for %s to be %q; got %q", tt.dialect, tt.expected, cmd)
+		}
+	}
+
+	if _, err = NewPaginator(Employee{}, "postgres", *u, OrderByAscNullsLast("unknown")); err == nil {
+		t.Error("expected an error when sorting by an unknown column")
+	}
+}
+
+func TestPaginator_Location(t *testing.T) {
+	type Employee struct {
+		ID         int       `paginate:"id"`
+		DateJoined time.Time `paginate:"filter"`
+		UpdatedAt  *time.Time
+		NullDate   NullTime
+	}
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	u, err := url.Parse("http://ottotech.com?date_joined>2021-01-31 10:00:00&date_joined<2021-02-01T10:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, Location(loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, date_joined, updated_at, null_date, count(*) over() FROM employee " +
+		"WHERE date_joined > $1 AND date_joined < $2 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	expectedArgs := []time.Time{
+		time.Date(2021, 1, 31, 8, 0, 0, 0, time.UTC),
+		time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if len(args) != len(expectedArgs) {
+		t.Fatalf("expected %d args; got %v", len(expectedArgs), args)
+	}
+	for i, arg := range args {
+		if tm, ok := arg.(time.Time); !ok || !tm.Equal(expectedArgs[i]) {
+			t.Errorf("expected arg %d to be %v; got %v", i, expectedArgs[i], arg)
+		}
+	}
+
+	// The scanned times should be converted to the location.
+	joined := time.Date(2021, 2, 1, 6, 0, 0, 0, time.UTC)
+	db, _ := newFakeDB(
+		[]string{"id", "date_joined", "updated_at", "null_date", "count"},
+		[][]driver.Value{{int64(1), joined, joined, joined, int64(1)}},
+	)
+	defer db.Close()
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	for paginator.NextData() {
+		e := Employee{}
+		if err = paginator.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		for _, tm := range []time.Time{e.DateJoined, *e.UpdatedAt, e.NullDate.Time} {
+			if tm.Location() != loc || !tm.Equal(joined) {
+				t.Errorf("expected %v in location %v; got %v", joined, loc, tm)
+			}
+		}
+	}
+
+	if _, err = NewPaginator(Employee{}, "postgres", *u, Location(nil)); err == nil {
+		t.Error("expected an error with a nil location")
+	}
+}
+
+func TestPaginator_Response_Total_Pages(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+
+	tests := []struct {
+		query       string
+		rows        [][]driver.Value
+		totalPages  int
+		hasNextPage bool
+	}{
+		{
+			query:       "page=1&page_size=2",
+			rows:        [][]driver.Value{{int64(1), "Ringo", int64(4)}, {int64(2), "John", int64(4)}},
+			totalPages:  2,
+			hasNextPage: true,
+		},
+		{
+			query:      "page=2&page_size=2",
+			rows:       [][]driver.Value{{int64(3), "Paul", int64(4)}, {int64(4), "George", int64(4)}},
+			totalPages: 2,
+		},
+		{
+			query:      "page=3&page_size=2",
+			rows:       [][]driver.Value{{int64(5), "Pete", int64(5)}},
+			totalPages: 3,
+		},
+		{
+			query: "page=1&page_size=2",
+			rows:  [][]driver.Value{},
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		db, _ := newFakeDB([]string{"id", "name", "count"}, tt.rows)
+		paginator, err := NewPaginator(Employee{}, "postgres", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = paginator.Execute(context.Background(), db); err != nil {
+			t.Fatal(err)
+		}
+		res := paginator.Response()
+		if res.TotalPages != tt.totalPages || res.HasNextPage != tt.hasNextPage {
+			t.Errorf("expected %d total pages and a next page %v for %s; got %+v", tt.totalPages, tt.hasNextPage, tt.query, res)
+		}
+		db.Close()
+	}
+}
+
+func TestPaginator_Response_Without_Scan(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com?page_size=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, _ := newFakeDB([]string{"id", "name", "count"}, [][]driver.Value{})
+	defer db.Close()
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	res := paginator.Response()
+	if res.TotalSize != 0 || res.PageCount != 0 || res.Partial {
+		t.Errorf("expected an empty and complete response; got %+v", res)
+	}
+
+	// The page count should be known even if the records are not scanned.
+	db, _ = newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{{int64(1), "Ringo", int64(12)}, {int64(2), "John", int64(12)}},
+	)
+	defer db.Close()
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	res = paginator.Response()
+	if res.TotalSize != 12 || res.PageCount != 2 {
+		t.Errorf("expected a page count of 2 out of 12 records; got %+v", res)
+	}
+
+	// Scanning after Response should not change the page count.
+	scanned := 0
+	for paginator.NextData() {
+		employee := Employee{}
+		if err = paginator.Scan(&employee); err != nil {
+			t.Fatal(err)
+		}
+		scanned++
+	}
+	if res = paginator.Response(); scanned != 2 || res.PageCount != 2 {
+		t.Errorf("expected to scan 2 records with a page count of 2; got %d and %+v", scanned, res)
+	}
+}
+
+func TestPaginator_Reset_And_Fetch_Two_Pages(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com?page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, conn := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{{int64(1), "Ringo", int64(4)}, {int64(2), "John", int64(4)}},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func() []Employee {
+		if err := paginator.Execute(context.Background(), db); err != nil {
+			t.Fatal(err)
+		}
+		results := make([]Employee, 0)
+		for paginator.NextData() {
+			employee := Employee{}
+			if err := paginator.Scan(&employee); err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, employee)
+		}
+		return results
+	}
+
+	if results := fetch(); len(results) != 2 || results[0].Name != "Ringo" {
+		t.Errorf("expected Ringo and John in the first page; got %v", results)
+	}
+	if res := paginator.Response(); res.PageNumber != 1 || !res.HasNextPage {
+		t.Errorf("expected the first page with a next page; got %+v", res)
+	}
+
+	// Without Reset the paginator is closed.
+	if err = paginator.Scan(&Employee{}); err != ErrPaginatorIsClosed {
+		t.Errorf("expected ErrPaginatorIsClosed; got %v", err)
+	}
+
+	paginator.Reset()
+	if err = paginator.SetPage(2); err != nil {
+		t.Fatal(err)
+	}
+	conn.rows = [][]driver.Value{{int64(3), "Paul", int64(4)}, {int64(4), "George", int64(4)}}
+
+	if results := fetch(); len(results) != 2 || results[0].Name != "Paul" || results[1].Name != "George" {
+		t.Errorf("expected Paul and George in the second page; got %v", results)
+	}
+	res := paginator.Response()
+	if res.PageNumber != 2 || res.PageCount != 2 || res.HasNextPage || !res.HasPreviousPage {
+		t.Errorf("expected the last page with 2 records; got %+v", res)
+	}
+
+	queries := conn.executedQueries()
+	expected := []string{
+		"SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 2 OFFSET 0",
+		"SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 2 OFFSET 2",
+	}
+	if !reflect.DeepEqual(queries, expected) {
+		t.Errorf("expected the queries %v; got %v", expected, queries)
+	}
+}
+
+func TestPaginator_Response_Page_Boundaries(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+
+	tests := []struct {
+		page            int
+		totalSize       int64
+		hasNextPage     bool
+		nextPageNumber  int
+		hasPreviousPage bool
+	}{
+		{page: 1, totalSize: 25, hasNextPage: true, nextPageNumber: 2},
+		{page: 2, totalSize: 25, hasNextPage: true, nextPageNumber: 3, hasPreviousPage: true},
+		{page: 3, totalSize: 25, hasPreviousPage: true},
+		{page: 4, totalSize: 25, hasPreviousPage: true},
+		{page: 2, totalSize: 20, hasPreviousPage: true},
+		{page: 3, totalSize: 20, hasPreviousPage: true},
+		{page: 1, totalSize: 10},
+		{page: 1, totalSize: 0},
+		{page: 2, totalSize: 0, hasPreviousPage: true},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(fmt.Sprintf("http://ottotech.com?page=%d&page_size=10", tt.page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The mariadb dialect counts the records with a separate query, so the
+		// total size is known even for the pages beyond the last one.
+		db, conn := newFakeDB([]string{"id", "name"}, [][]driver.Value{})
+		conn.count = tt.totalSize
+		paginator, err := NewPaginator(Employee{}, "mariadb", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = paginator.Execute(context.Background(), db); err != nil {
+			t.Fatal(err)
+		}
+		res := paginator.Response()
+		if res.HasNextPage != tt.hasNextPage || res.NextPageNumber != tt.nextPageNumber || res.HasPreviousPage != tt.hasPreviousPage {
+			t.Errorf("unexpected response for page %d of %d records: %+v", tt.page, tt.totalSize, res)
+		}
+		db.Close()
+	}
+}
+
+func TestPaginator_Response_Safety_Limits(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+
+	tests := []struct {
+		query           string
+		expected        string
+		pageSizeClamped bool
+		offsetClamped   bool
+	}{
+		{
+			query:           "page_size=1000000",
+			expected:        "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 100 OFFSET 0",
+			pageSizeClamped: true,
+		},
+		{
+			query:         "page=50&page_size=20",
+			expected:      "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 20 OFFSET 500",
+			offsetClamped: true,
+		},
+		{
+			query:    "page=3&page_size=20",
+			expected: "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 20 OFFSET 40",
+		},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, MaxPageSize(100), MaxOffset(500))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != tt.expected {
+			t.Errorf("expected sql command for %s to be %q; got %q", tt.query, tt.expected, cmd)
+		}
+		res := paginator.Response()
+		if res.PageSizeClamped != tt.pageSizeClamped || res.OffsetClamped != tt.offsetClamped {
+			t.Errorf("expected the clamp flags for %s to be %v and %v; got %+v", tt.query, tt.pageSizeClamped, tt.offsetClamped, res)
+		}
+	}
+}
+
+func TestNewPaginator_MaxOffset_With_StrictPaging(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+
+	// The offset of the page 50 would be 980, beyond the max offset.
+	u, err := url.Parse("http://ottotech.com?page=50&page_size=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewPaginator(Employee{}, "postgres", *u, MaxOffset(500), StrictPaging()); !errors.Is(err, ErrOffsetTooDeep) {
+		t.Errorf("expected ErrOffsetTooDeep; got %v", err)
+	}
+
+	u, err = url.Parse("http://ottotech.com?page=26&page_size=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, MaxOffset(500), StrictPaging())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 20 OFFSET 500"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	// The rejected page should not change the current page.
+	if err = paginator.SetPage(27); !errors.Is(err, ErrOffsetTooDeep) {
+		t.Errorf("expected ErrOffsetTooDeep when setting the page 27; got %v", err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+}
+
+func TestNewPaginator_LimitOffset(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+
+	tests := []struct {
+		query    string
+		opts     []Option
+		expected string
+	}{
+		// The limit and the offset are ignored without the option.
+		{"limit=10&offset=45", nil, "LIMIT 30 OFFSET 0"},
+		{"limit=10&offset=45", []Option{LimitOffset()}, "LIMIT 10 OFFSET 45"},
+		{"offset=45", []Option{LimitOffset()}, "LIMIT 30 OFFSET 45"},
+		// The page-based pagination still works with the option.
+		{"page=3&page_size=10", []Option{LimitOffset()}, "LIMIT 10 OFFSET 20"},
+		// The limit and the offset take precedence over the page size and the page.
+		{"page=3&page_size=10&limit=20&offset=5", []Option{LimitOffset()}, "LIMIT 20 OFFSET 5"},
+		// Invalid values are ignored.
+		{"page=3&page_size=10&limit=-1&offset=abc", []Option{LimitOffset()}, "LIMIT 10 OFFSET 20"},
+		{"limit=10&offset=150", []Option{LimitOffset(), MaxOffset(100)}, "LIMIT 10 OFFSET 100"},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "SELECT id, name, count(*) over() FROM employee ORDER BY id " + tt.expected
+		if cmd != expected {
+			t.Errorf("%s: expected sql command to be %q; got %q", tt.query, expected, cmd)
+		}
+	}
+
+	strict := []struct {
+		query string
+		err   error
+	}{
+		{"page=3&offset=5", nil},
+		{"page_size=10&limit=20", nil},
+		{"offset=-5", nil},
+		{"limit=0", nil},
+		{"limit=10&offset=150", ErrOffsetTooDeep},
+	}
+	for _, tt := range strict {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = NewPaginator(Employee{}, "postgres", *u, LimitOffset(), StrictPaging(), MaxOffset(100))
+		if err == nil {
+			t.Errorf("%s: expected an error with StrictPaging", tt.query)
+		}
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v; got %v", tt.query, tt.err, err)
+		}
+	}
+}
+
+func TestPaginator_LimitOffset_Response(t *testing.T) {
+	type Employee struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com?limit=10&offset=45")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{{int64(46), "Ringo", int64(100)}},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, LimitOffset())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	for paginator.NextData() {
+		if err = paginator.Scan(&Employee{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The offset 45 is in the fifth page of 10 records.
+	res := paginator.Response()
+	if res.PageNumber != 5 || res.PageSize != 10 || res.Offset != 45 || res.TotalPages != 10 {
+		t.Errorf("unexpected pagination metadata %+v", res)
+	}
+	if !res.HasNextPage || !res.HasPreviousPage || res.NextPageNumber != 6 {
+		t.Errorf("expected the response to have next and previous pages; got %+v", res)
+	}
+
+	// SetPage goes back to the page-based pagination.
+	if err = paginator.SetPage(2); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM employee ORDER BY id LIMIT 10 OFFSET 10"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if res = paginator.Response(); res.Offset != 10 || res.PageNumber != 2 {
+		t.Errorf("expected the offset 10 of the page 2; got %+v", res)
+	}
+}
+
+func TestParseCamelCaseToSnakeLowerCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"ID", "id"},
+		{"Name", "name"},
+		{"LastName", "last_name"},
+		{"myCamelCaseVar", "my_camel_case_var"},
+		{"UserID", "user_id"},
+		{"HTTPStatus", "http_status"},
+		{"APIKey", "api_key"},
+		{"OAuth2Token", "o_auth2_token"},
+		{"Address2", "address2"},
+		{"Line2Name", "line2_name"},
+		{"already_snake", "already_snake"},
+		{"Null_Date", "null_date"},
+	}
+	for _, tt := range tests {
+		if got := parseCamelCaseToSnakeLowerCase(tt.name); got != tt.expected {
+			t.Errorf("expected %q to be parsed as %q; got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestNewPaginator_ColumnNamer(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string `paginate:"filter;col=surname"`
+	}
+	u, err := url.Parse("http://ottotech.com?emp_name=Ringo&surname=Starr&sort=-emp_name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := func(fieldName string) string {
+		return "emp_" + parseCamelCaseToSnakeLowerCase(fieldName)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, ColumnNamer(prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fields with the tag "col" keep their column names.
+	expected := "SELECT emp_id, emp_name, surname, count(*) over() FROM employee " +
+		"WHERE emp_name = $1 AND surname = $2 ORDER BY emp_name DESC,emp_id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if fmt.Sprint(args) != "[Ringo Starr]" {
+		t.Errorf("expected args to be [Ringo Starr]; got %v", args)
+	}
+
+	// Other paginators of the same table should keep the default column names.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, ColumnNamer(strings.ToUpper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT ID, NAME, surname, count(*) over() FROM employee WHERE surname = $1 ORDER BY ID LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, surname, count(*) over() FROM employee WHERE surname = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	invalid := func(fieldName string) string { return fieldName + "; DROP TABLE employee" }
+	if _, err = NewPaginator(Employee{}, "postgres", *u, ColumnNamer(invalid)); err == nil {
+		t.Error("expected an error with invalid inferred column names")
+	}
+	if _, err = NewPaginator(Employee{}, "postgres", *u, ColumnNamer(nil)); err == nil {
+		t.Error("expected an error with a nil column namer")
+	}
+}
+
+func TestNewPaginator_PageSize_Overrides_Request_Page_Size(t *testing.T) {
+	type Person struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com?page=2&page_size=50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Person{}, "postgres", *u, PageSize(15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM person ORDER BY id LIMIT 15 OFFSET 15"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if res := paginator.Response(); res.PageSize != 15 {
+		t.Errorf("expected a page size of 15; got %d", res.PageSize)
+	}
+}
+
+func TestPaginate_OrderByAsc_Without_Sort_Parameter(t *testing.T) {
+	type Person struct {
+		ID   int `paginate:"id"`
+		Name string
+	}
+	u, err := url.Parse("http://ottotech.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Person{}, "postgres", *u, OrderByAsc("name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, _, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, count(*) over() FROM person ORDER BY name ASC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+
+	c := make(chan string)
+	customs := customOrderByClauses{{column: "name", sorting: "ASC"}}
+	go createOrderByClause("postgres", parameters{}, "sort", []string{"id", "name"}, customs, []string{"id"}, nil, false, c)
+	if clause := <-c; clause != " ORDER BY name ASC,id" {
+		t.Errorf("expected clause to be %q; got %q", " ORDER BY name ASC,id", clause)
+	}
+}
+
+func TestNewPaginator_Table_Metadata_Is_Not_Shared(t *testing.T) {
+	type Employee struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string `paginate:"filter;param=surname"`
+		Age      int
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := NewPaginator(Employee{}, "postgres", *u, ExcludeColumns("Age"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err = url.Parse("http://ottotech.com?surname=Star&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, args, err := first.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, last_name, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected || len(args) != 1 || args[0] != "Ringo" {
+		t.Errorf("expected sql command %q with args [Ringo]; got %q with %v", expected, cmd, args)
+	}
+
+	cmd, args, err = second.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, last_name, age, count(*) over() FROM employee WHERE last_name = $1 ORDER BY id LIMIT 30 OFFSET 30"
+	if cmd != expected || len(args) != 1 || args[0] != "Star" {
+		t.Errorf("expected sql command %q with args [Star]; got %q with %v", expected, cmd, args)
 	}
 }
 
-func TestCreatePaginationClause_with_page_eq_1(t *testing.T) {
-	pageNumber := 1
-	pageSize := 30
-	c := make(chan string)
-	go createPaginationClause(pageNumber, pageSize, c)
-	clause := <-c
-	expectedCLAUSE := " LIMIT 30 OFFSET 0"
-	if clause != expectedCLAUSE {
-		t.Errorf("expected clause should be %v; got %v", expectedCLAUSE, clause)
+func BenchmarkNewPaginator(b *testing.B) {
+	type Employee struct {
+		ID         int       `paginate:"id"`
+		Name       string    `paginate:"filter"`
+		LastName   string    `paginate:"filter;param=surname"`
+		Salary     float64   `paginate:"filter"`
+		DateJoined time.Time `paginate:"filter;fn=DATE"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo&surname=Star&page=2&sort=-salary")
+	if err != nil {
+		b.Fatal(err)
 	}
+	t := reflect.TypeOf(Employee{})
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := NewPaginator(Employee{}, "postgres", *u); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tableMetadataCache.Delete(t)
+			if _, err := NewPaginator(Employee{}, "postgres", *u); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
 
-func TestCreatePaginationClause_with_page_lt_1(t *testing.T) {
-	pageNumber := -4
-	pageSize := 30
-	c := make(chan string)
-	go createPaginationClause(pageNumber, pageSize, c)
-	clause := <-c
-	expectedCLAUSE := " LIMIT 30 OFFSET 0"
-	if clause != expectedCLAUSE {
-		t.Errorf("expected clause should be %v; got %v", expectedCLAUSE, clause)
+func TestPaginator_ForEach(t *testing.T) {
+	type Employee struct {
+		ID       int     `paginate:"id"`
+		Name     string  `paginate:"filter"`
+		NullText *string `paginate:"col=null_text"`
+	}
+	u, err := url.Parse("http://ottotech.com?page_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := newFakeDB(
+		[]string{"id", "name", "null_text", "count"},
+		[][]driver.Value{
+			{int64(1), "Ringo", nil, int64(3)},
+			{int64(2), "John", "guitar", int64(3)},
+		},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	employees := make([]Employee, 0)
+	err = paginator.ForEach(context.Background(), db, func(row interface{}) error {
+		employees = append(employees, row.(Employee))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(employees) != 2 || employees[0].Name != "Ringo" || employees[0].NullText != nil ||
+		employees[1].Name != "John" || employees[1].NullText == nil || *employees[1].NullText != "guitar" {
+		t.Errorf("expected the scanned employees Ringo and John; got %+v", employees)
+	}
+	if paginator.NextData() {
+		t.Error("expected no rows left for NextData after ForEach")
+	}
+	if paginator.Remaining() != 0 || paginator.Consumed() != 2 {
+		t.Errorf("expected 0 remaining and 2 consumed rows; got %d and %d", paginator.Remaining(), paginator.Consumed())
+	}
+	response := paginator.Response()
+	if response.PageCount != 2 || response.TotalSize != 3 || response.TotalPages != 2 || response.Partial {
+		t.Errorf("expected a complete response with 2 of 3 records; got %+v", response)
+	}
+
+	// The iteration stops at the first error of the callback.
+	paginator.Reset()
+	errStop := errors.New("stop")
+	calls := 0
+	err = paginator.ForEach(context.Background(), db, func(row interface{}) error {
+		calls++
+		return errStop
+	})
+	if err != errStop {
+		t.Errorf("expected the error of the callback; got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the callback to be called once; got %d", calls)
+	}
+
+	if err = paginator.ForEach(context.Background(), db, nil); err == nil {
+		t.Error("expected an error with a nil callback")
 	}
 }
 
-func TestCreateOrderByClause_with_sorting_options(t *testing.T) {
-	colNames := []string{"id", "name", "lastname", "age", "address"}
-	params := parameters{{"sort", "=", "+name,-lastname,-age,+address"}}
-	c := make(chan string)
-	go createOrderByClause(params, colNames, customOrderByClauses{}, "id", c)
-	clause := <-c
-	expectedCLAUSE := " ORDER BY name ASC,lastname DESC,age DESC,address ASC,id"
-	if clause != expectedCLAUSE {
-		t.Errorf("expected clause should be %v, got %v", expectedCLAUSE, clause)
+func BenchmarkPaginator_ForEach(b *testing.B) {
+	type Employee struct {
+		ID     int     `paginate:"id"`
+		Name   string  `paginate:"filter"`
+		Salary float64 `paginate:"filter"`
 	}
+	u, err := url.Parse("http://ottotech.com?page_size=1000")
+	if err != nil {
+		b.Fatal(err)
+	}
+	rows := make([][]driver.Value, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		rows = append(rows, []driver.Value{int64(i), "Ringo", 4000.5, int64(1000)})
+	}
+	db, _ := newFakeDB([]string{"id", "name", "salary", "count"}, rows)
+	defer db.Close()
+	ctx := context.Background()
+
+	b.Run("Execute", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			paginator, err := NewPaginator(Employee{}, "postgres", *u)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err = paginator.Execute(ctx, db); err != nil {
+				b.Fatal(err)
+			}
+			for paginator.NextData() {
+				var employee Employee
+				if err = paginator.Scan(&employee); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("ForEach", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			paginator, err := NewPaginator(Employee{}, "postgres", *u)
+			if err != nil {
+				b.Fatal(err)
+			}
+			err = paginator.ForEach(ctx, db, func(row interface{}) error {
+				_ = row.(Employee)
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
 
-func TestCreateOrderByClause_with_no_sorting_options(t *testing.T) {
-	colNames := []string{"name", "lastname", "age", "address"}
-	params := parameters{}
-	c := make(chan string)
-	go createOrderByClause(params, colNames, customOrderByClauses{}, "id", c)
-	clause := <-c
-	expectedCLAUSE := " ORDER BY id"
-	if clause != expectedCLAUSE {
-		t.Errorf("expected clause should be %v, got %v", expectedCLAUSE, clause)
+func TestPaginate_NoSelect_Columns(t *testing.T) {
+	type Employee struct {
+		ID    int    `paginate:"id;noselect"`
+		Name  string `paginate:"filter"`
+		OrgID int    `paginate:"filter;noselect;col=org_id"`
+	}
+	u, err := url.Parse("http://ottotech.com?org_id=3&sort=-org_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The id is always selected.
+	expected := "SELECT id, name, count(*) over() FROM employee WHERE org_id = $1 ORDER BY org_id DESC,id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if len(args) != 1 || args[0] != "3" {
+		t.Errorf("expected args [3]; got %v", args)
+	}
+
+	db, _ := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{{int64(1), "Ringo", int64(1)}},
+	)
+	defer db.Close()
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	for paginator.NextData() {
+		employee := Employee{}
+		if err = paginator.Scan(&employee); err != nil {
+			t.Fatal(err)
+		}
+		if employee.ID != 1 || employee.Name != "Ringo" || employee.OrgID != 0 {
+			t.Errorf("expected the employee Ringo without an org id; got %+v", employee)
+		}
+	}
+}
+
+func TestPaginator_AppliedFilters(t *testing.T) {
+	type Employee struct {
+		ID       int      `paginate:"id;filter"`
+		Name     string   `paginate:"filter"`
+		Salary   float64  `paginate:"filter"`
+		NullDate NullTime `paginate:"filter"`
+		Age      int      `paginate:"filter;param=years"`
+	}
+	u, err := url.Parse("http://ottotech.com?salary>=4000&salary<=8000&name=~ringo&id=1&id=2&null_date=null&years=30..40&sort=-salary&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []AppliedFilter{
+		{Column: "id", Operator: "IN", Value: "1,2"},
+		{Column: "name", Operator: "LIKE", Value: "ringo"},
+		{Column: "salary", Operator: ">=", Value: "4000"},
+		{Column: "salary", Operator: "<=", Value: "8000"},
+		{Column: "null_date", Operator: "IS NULL", Value: "null"},
+		{Column: "age", Operator: "BETWEEN", Value: "30..40"},
+	}
+	if filters := paginator.AppliedFilters(); !reflect.DeepEqual(filters, expected) {
+		t.Errorf("expected the applied filters to be %+v; got %+v", expected, filters)
+	}
+
+	u, err = url.Parse("http://ottotech.com?sort=-salary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filters := paginator.AppliedFilters(); len(filters) != 0 {
+		t.Errorf("expected no applied filters; got %+v", filters)
+	}
+}
+
+func TestPaginate_Bool_Filters(t *testing.T) {
+	type Employee struct {
+		ID       int      `paginate:"id"`
+		Active   bool     `paginate:"filter"`
+		NullBool NullBool `paginate:"filter"`
+		Remote   *bool    `paginate:"filter"`
+	}
+
+	tests := []struct {
+		query        string
+		expectedArgs []interface{}
+	}{
+		{"active=true", []interface{}{true}},
+		{"active=FALSE", []interface{}{false}},
+		{"null_bool=1&remote<>0", []interface{}{true, false}},
+		{"active=0&active=1", []interface{}{false, true}},
+		{"null_bool=null", []interface{}{}},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse("http://ottotech.com?" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, args, err := paginator.Paginate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(args) != len(tt.expectedArgs) || (len(args) > 0 && !reflect.DeepEqual(args, tt.expectedArgs)) {
+			t.Errorf("expected the args of %q to be %v; got %v", tt.query, tt.expectedArgs, args)
+		}
+	}
+
+	for _, query := range []string{"active=yes", "null_bool=2", "active=true&active=t"} {
+		u, err := url.Parse("http://ottotech.com?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paginator, err := NewPaginator(Employee{}, "postgres", *u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err = paginator.Paginate(); err == nil {
+			t.Errorf("expected an error with the invalid boolean filter %q", query)
+		}
+	}
+}
+
+func TestPaginator_OnQuery(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type query struct {
+		sql  string
+		args []interface{}
+	}
+	queries := make([]query, 0)
+	hook := func(sql string, args []interface{}, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Errorf("expected a non-negative elapsed time; got %s", elapsed)
+		}
+		queries = append(queries, query{sql, args})
+	}
+
+	db, _ := newFakeDB(
+		[]string{"id", "name", "count"},
+		[][]driver.Value{{int64(1), "Ringo", int64(1)}},
+	)
+	defer db.Close()
+
+	paginator, err := NewPaginator(Employee{}, "postgres", *u, OnQuery(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	expected := []query{{
+		sql:  "SELECT id, name, count(*) over() FROM employee WHERE name = $1 ORDER BY id LIMIT 30 OFFSET 0",
+		args: []interface{}{"Ringo"},
+	}}
+	if !reflect.DeepEqual(queries, expected) {
+		t.Errorf("expected the hook to receive the queries %v; got %v", expected, queries)
+	}
+
+	countDB, _ := newFakeDB([]string{"count"}, [][]driver.Value{{int64(1)}})
+	defer countDB.Close()
+
+	queries = queries[:0]
+	if _, err = paginator.Count(context.Background(), countDB); err != nil {
+		t.Fatal(err)
+	}
+	expected = []query{{
+		sql:  "SELECT count(*) FROM employee WHERE name = $1",
+		args: []interface{}{"Ringo"},
+	}}
+	if !reflect.DeepEqual(queries, expected) {
+		t.Errorf("expected the hook to receive the queries %v; got %v", expected, queries)
+	}
+
+	// A nil hook is ignored.
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, OnQuery(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = paginator.Execute(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateTable(t *testing.T) {
+	type Employee struct {
+		ID                  int        `paginate:"id"`
+		Name                string     `paginate:"filter"`
+		LastName            string     `paginate:"filter;param=surname"`
+		DateJoined          time.Time  `paginate:"filter;fn=DATE"`
+		Meta                string     `paginate:"filter;json"`
+		Tags                string     `paginate:"filter;array"`
+		OrgID               int        `paginate:"filter;noselect;col=org_id"`
+		ProgrammingLanguage NullString `paginate:"filter;col=developer.programming_language"`
+		Notes               *string
+	}
+	if err := ValidateTable(Employee{}); err != nil {
+		t.Errorf("expected a valid table; got %v", err)
+	}
+
+	type NoID struct {
+		Name string
+	}
+	type InvalidType struct {
+		ID    int `paginate:"id"`
+		Names []string
+	}
+	type UnknownTag struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filtr"`
+	}
+	type MalformedTag struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter;col"`
+	}
+	type InvalidColumn struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"col=name;--"`
+	}
+	type InvalidParam struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter;param=name<"`
+	}
+	type SameColumn struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		FullName string `paginate:"col=name"`
+	}
+	type SameParam struct {
+		ID       int    `paginate:"id"`
+		Name     string `paginate:"filter"`
+		LastName string `paginate:"filter;param=name"`
+	}
+
+	tests := []struct {
+		table    interface{}
+		expected string
+	}{
+		{nil, "paginate: table should be of struct type"},
+		{&Employee{}, "paginate: table should be of struct type"},
+		{Employee{Name: "Ringo"}, "paginate: table struct should be empty with only the default zero values"},
+		{NoID{}, "paginate: id has not been defined in any of the field of the given struct"},
+		{InvalidType{}, `paginate: invalid type for field "Names"`},
+		{UnknownTag{}, `paginate: unknown tag "filtr" for field "Name"`},
+		{MalformedTag{}, `paginate: unknown tag "col" for field "Name"`},
+		{InvalidColumn{}, `paginate: unknown tag "--" for field "Name"`},
+		{InvalidParam{}, `paginate: invalid parameter name "name<" for field "Name"`},
+		{SameColumn{}, `paginate: fields "Name" and "FullName" have the same column "name"`},
+		{SameParam{}, `paginate: more than one field is filtered with the request parameter "name"`},
+	}
+	for _, tt := range tests {
+		err := ValidateTable(tt.table)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("expected the error %q for %T; got %v", tt.expected, tt.table, err)
+		}
+	}
+}
+
+func TestPaginate_JSON_Filters(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+		Meta string `paginate:"filter;json"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo&meta.role=admin&meta.team.name=core&meta.level>=3&meta.role';--=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, meta, count(*) over() FROM employee WHERE name = $1 AND meta->>'role' = $2 AND " +
+		"meta->'team'->>'name' = $3 AND meta->>'level' >= $4 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"Ringo", "admin", "core", "3"}) {
+		t.Errorf("expected args [Ringo admin core 3]; got %v", args)
+	}
+
+	// Repeated keys will be interpreted as an IN sql clause.
+	u, err = url.Parse("http://ottotech.com?meta.role=admin&meta.role=owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, meta, count(*) over() FROM employee WHERE meta->>'role' IN($1,$2) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected || len(args) != 2 {
+		t.Errorf("expected sql command to be %q with 2 args; got %q with %v", expected, cmd, args)
+	}
+
+	if _, err = NewPaginator(Employee{}, "mysql", *u); err == nil {
+		t.Errorf("expected an error when using json filters with mysql")
+	}
+}
+
+func TestPaginate_JSON_Filters_With_Param_Tag(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Meta string `paginate:"filter;json;param=m"`
+	}
+	u, err := url.Parse("http://ottotech.com?m.role=admin&meta.team=core")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, meta, count(*) over() FROM employee WHERE meta->>'role' = $1 ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected || len(args) != 1 || args[0] != "admin" {
+		t.Errorf("expected sql command to be %q with args [admin]; got %q with %v", expected, cmd, args)
+	}
+}
+
+func TestPaginate_Array_Filters(t *testing.T) {
+	type Employee struct {
+		ID   int    `paginate:"id"`
+		Name string `paginate:"filter"`
+		Tags string `paginate:"filter;array"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=Ringo&tags=go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err := NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, tags, count(*) over() FROM employee WHERE name = $1 AND $2 = ANY(tags) ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"Ringo", "go"}) {
+		t.Errorf("expected args [Ringo go]; got %v", args)
+	}
+
+	// Repeated parameters will match the arrays containing any of the values.
+	u, err = url.Parse("http://ottotech.com?tags=go&tags=rust")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "postgres", *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT id, name, tags, count(*) over() FROM employee WHERE tags && ARRAY[$1,$2] ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"go", "rust"}) {
+		t.Errorf("expected args [go rust]; got %v", args)
+	}
+
+	if _, err = NewPaginator(Employee{}, "mysql", *u); err == nil {
+		t.Errorf("expected an error when using array filters with mysql")
+	}
+}
+
+func TestPaginate_TimeLayout(t *testing.T) {
+	type Employee struct {
+		ID         int       `paginate:"id"`
+		Name       string    `paginate:"filter"`
+		DateJoined time.Time `paginate:"filter"`
+	}
+	u, err := url.Parse("http://ottotech.com?name=2021-01-02&date_joined=2021-01-02..2021-02-01&date_joined<>2021-01-15")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginator, err := NewPaginator(Employee{}, "mysql", *u, TimeLayout("2006-01-02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, args, err := paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT id, name, date_joined, count(*) over() FROM employee WHERE name = ? AND " +
+		"date_joined BETWEEN ? AND ? AND date_joined <> ? ORDER BY id LIMIT 30 OFFSET 0"
+	if cmd != expected {
+		t.Errorf("expected sql command to be %q; got %q", expected, cmd)
+	}
+	// Only the values of the time columns are parsed.
+	expectedArgs := []interface{}{
+		"2021-01-02",
+		time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args to be %v; got %v", expectedArgs, args)
+	}
+
+	// The layout is interpreted in the given location.
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	paginator, err = NewPaginator(Employee{}, "postgres", *u, TimeLayout("2006-01-02"), Location(loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, args, err = paginator.Paginate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 4 || !args[1].(time.Time).Equal(time.Date(2021, 1, 2, 0, 0, 0, 0, loc)) {
+		t.Errorf("expected the times to be in the given location; got %v", args)
+	}
+
+	u, err = url.Parse("http://ottotech.com?date_joined>2021-01-02T10:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paginator, err = NewPaginator(Employee{}, "mysql", *u, TimeLayout("2006-01-02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = paginator.Paginate(); err == nil {
+		t.Error("expected an error with a time that does not have the given layout")
+	}
+
+	if _, err = NewPaginator(Employee{}, "mysql", *u, TimeLayout(" ")); err == nil {
+		t.Error("expected an error with an empty time layout")
 	}
 }
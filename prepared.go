@@ -0,0 +1,88 @@
+package paginate
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PreparedPaginator holds a prepared statement of the sql command created by
+// Paginator.Paginate, whose LIMIT and OFFSET values are given as arguments when
+// executing the statement. This makes fetching multiple pages faster, since the
+// sql command is prepared only once. Use Paginator.Prepare to create one, e.g.:
+//
+//	prepared, err := paginator.Prepare(ctx, db)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer prepared.Close()
+//
+//	for page := 1; ; page++ {
+//		pag, err := prepared.Page(ctx, page)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		for pag.NextData() {
+//			...
+//		}
+//		if !pag.Response().HasNextPage {
+//			break
+//		}
+//	}
+//
+// A PreparedPaginator is not safe for concurrent use.
+type PreparedPaginator struct {
+	p    *paginator
+	stmt *sql.Stmt
+	args []interface{}
+
+	// countStmt is the prepared count query used to get the
+	// total number of records when separateCount is true.
+	countStmt *sql.Stmt
+	countArgs []interface{}
+}
+
+// Page executes the prepared statement to fetch the records of the given page
+// and returns the Paginator holding them, so they can be read with NextData and
+// Scan. The returned Paginator is reused by subsequent calls to Page, so read the
+// records of a page before fetching the next one.
+func (pp *PreparedPaginator) Page(ctx context.Context, n int) (Paginator, error) {
+	if err := pp.p.SetPage(n); err != nil {
+		return nil, err
+	}
+	pp.p.Reset()
+
+	if pp.p.matchesNothing {
+		pp.p.scanned = true
+		return pp.p, nil
+	}
+
+	if pp.countStmt != nil {
+		err := pp.countStmt.QueryRowContext(ctx, pp.countArgs...).Scan(&pp.p.totalSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	args := append([]interface{}{}, pp.args...)
+	args = append(args, pp.p.pageSize, pp.p.currentOffset())
+
+	rows, err := pp.stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := pp.p.scanRows(rows); err != nil {
+		return nil, err
+	}
+	return pp.p, nil
+}
+
+// Close closes the prepared statements.
+func (pp *PreparedPaginator) Close() error {
+	if pp.countStmt != nil {
+		if err := pp.countStmt.Close(); err != nil {
+			pp.stmt.Close()
+			return err
+		}
+	}
+	return pp.stmt.Close()
+}
@@ -1,6 +1,10 @@
 package paginate
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type parameters []parameter
 
@@ -33,11 +37,57 @@ type paginationRequest struct {
 // Clients of this package can use PaginationResponse to paginate further their data.
 type PaginationResponse struct {
 	PageNumber      int  `json:"page_number"`
+	PageSize        int  `json:"page_size"`
 	NextPageNumber  int  `json:"next_page_number"`
 	HasNextPage     bool `json:"has_next_page"`
 	HasPreviousPage bool `json:"has_previous_page"`
 	PageCount       int  `json:"page_count"`
 	TotalSize       int  `json:"total_size"`
+
+	// TotalPages is the number of pages needed to show all the records
+	// of TotalSize with the page size of PageSize, or zero if there are
+	// no records.
+	TotalPages int `json:"total_pages"`
+
+	// Offset is the number of records skipped to reach the page, either
+	// given directly with the LimitOffset option or computed from the
+	// page number.
+	Offset int `json:"offset,omitempty"`
+
+	// PageSizeClamped and OffsetClamped are true when the requested page
+	// size or page were reduced by the safety limits of the MaxPageSize
+	// and MaxOffset options.
+	PageSizeClamped bool `json:"page_size_clamped,omitempty"`
+	OffsetClamped   bool `json:"offset_clamped,omitempty"`
+
+	// Partial is true when the paginated records have not been scanned
+	// completely, e.g. because the query or the scanning failed. In that
+	// case only PageNumber and PageSize, which come from the request, are
+	// reliable, while PageCount, TotalSize and TotalPages will be zero and
+	// HasNextPage will be false.
+	Partial bool `json:"partial,omitempty"`
+
+	// NextCursor is the continuation token of the next page when
+	// the KeysetPagination option is used.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AppliedFilter describes a filter of the request url that Paginator applies to
+// the records, e.g. ``salary>=4000`` is described by the column "salary", the
+// operator ">=" and the value "4000". Clients of this package can use the applied
+// filters, for example, to show the active filters of a listing.
+type AppliedFilter struct {
+	// Column is the name of the filtered column, or the json path of the
+	// filtered key of a json column, e.g. meta->>'role'.
+	Column string `json:"column"`
+
+	// Operator is the sql operator of the filter, e.g. "=", ">=", "IN",
+	// "LIKE", "BETWEEN" or "IS NULL".
+	Operator string `json:"operator"`
+
+	// Value is the value of the filter as given in the request url, e.g.
+	// "4000..8000" for BETWEEN, "go,rust" for IN or "null" for IS NULL.
+	Value string `json:"value"`
 }
 
 // whereClause holds information about an sql where clause.
@@ -45,6 +95,10 @@ type whereClause struct {
 	clause string
 	args   []interface{}
 	exists bool
+
+	// matchesNothing is true when the clause can never match a record,
+	// e.g. when it has an empty IN clause that is ANDed with the rest.
+	matchesNothing bool
 }
 
 // mappers holds a collection of mapper objects.
@@ -84,9 +138,138 @@ func (m *mappers) Add(col, param string) {
 	})
 }
 
-type __dialectPlaceholder map[string]string
+// placeholderStyle renders the placeholders of the arguments of the sql commands.
+// The sql commands are built with the question mark placeholder (see _placeholder)
+// and rendered with the placeholderStyle of the dialect at the end, so adding a new
+// style only requires a new implementation of this interface.
+type placeholderStyle interface {
+	// placeholder returns the placeholder of the n-th argument, starting at 1.
+	placeholder(n int) string
+}
+
+// questionPlaceholder renders the placeholders as "?", e.g. for mysql.
+type questionPlaceholder struct{}
+
+func (questionPlaceholder) placeholder(int) string { return "?" }
+
+// dollarPlaceholder renders the placeholders as "$1", "$2", etc., e.g. for postgres.
+type dollarPlaceholder struct{}
+
+func (dollarPlaceholder) placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+// atPlaceholder renders the placeholders as "@p1", "@p2", etc., e.g. for sql server.
+type atPlaceholder struct{}
+
+func (atPlaceholder) placeholder(n int) string { return "@p" + strconv.Itoa(n) }
+
+// colonPlaceholder renders the placeholders as ":1", ":2", etc., e.g. for oracle.
+type colonPlaceholder struct{}
+
+func (colonPlaceholder) placeholder(n int) string { return ":" + strconv.Itoa(n) }
+
+// placeholderStyles holds the placeholder styles that can be given
+// with the PlaceholderStyle option, keyed by their prefix.
+var placeholderStyles = map[string]placeholderStyle{
+	"?":  questionPlaceholder{},
+	"$":  dollarPlaceholder{},
+	"@p": atPlaceholder{},
+	":":  colonPlaceholder{},
+}
+
+// renderPlaceholders replaces the question mark placeholders of the given sql
+// command of the given dialect, in order, with the placeholders of the given
+// style. The whole command is numbered in a single pass, so the placeholders of
+// all its clauses, e.g. joins, where, having and "ORDER BY" clauses, are sequential.
+func renderPlaceholders(sql, dialect string, style placeholderStyle) string {
+	if _, ok := style.(questionPlaceholder); ok {
+		return sql
+	}
+	return replacePlaceholders(sql, dialect, style.placeholder)
+}
+
+// replacePlaceholders replaces the question mark placeholders of the given sql
+// command of the given dialect with the result of calling replace with their
+// position, starting at 1. The question marks inside string literals, e.g.
+// 'why?', and inside comments, e.g. /* why? */ or -- why?, are not placeholders
+// and are left as they are. For mysql and mariadb the backslash escapes of the
+// string literals, e.g. 'it\'s', and the # comments are taken into account.
+func replacePlaceholders(sql, dialect string, replace func(n int) string) string {
+	mysql := dialect == "mysql" || dialect == "mariadb"
+
+	// skipTo returns the index where the string literal or the comment starting
+	// at i ends, i.e. the index of its last character.
+	skipTo := func(i int) int {
+		switch {
+		case sql[i] == '\'':
+			// An escaped quote ('') ends the literal and starts another one.
+			for j := i + 1; j < len(sql); j++ {
+				if mysql && sql[j] == '\\' {
+					j++
+				} else if sql[j] == '\'' {
+					return j
+				}
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			if j := strings.Index(sql[i+2:], "*/"); j != -1 {
+				return i + 2 + j + 1
+			}
+		default:
+			if j := strings.IndexByte(sql[i:], '\n'); j != -1 {
+				return i + j
+			}
+		}
+		return len(sql) - 1
+	}
+
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'', strings.HasPrefix(sql[i:], "/*"), isLineComment(sql[i:], mysql):
+			end := skipTo(i)
+			b.WriteString(sql[i : end+1])
+			i = end
+			continue
+		case c == _placeholder[0]:
+			n++
+			b.WriteString(replace(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// isLineComment checks whether the given sql starts with a comment that runs until
+// the end of the line. For mysql and mariadb "--" should be followed by a space to
+// start a comment, and "#" starts a comment too.
+func isLineComment(sql string, mysql bool) bool {
+	if mysql && strings.HasPrefix(sql, "#") {
+		return true
+	}
+	if !strings.HasPrefix(sql, "--") {
+		return false
+	}
+	return !mysql || len(sql) == 2 || sql[2] == ' ' || sql[2] == '\t' || sql[2] == '\n' || sql[2] == '\r'
+}
+
+// countPlaceholders returns the number of question mark placeholders of the given
+// sql command of the given dialect, leaving out the ones inside string literals
+// and comments. See replacePlaceholders.
+func countPlaceholders(sql, dialect string) int {
+	count := 0
+	replacePlaceholders(sql, dialect, func(n int) string {
+		count = n
+		return _placeholder
+	})
+	return count
+}
+
+type __dialectPlaceholder map[string]placeholderStyle
 
-func (d __dialectPlaceholder) GetPlaceHolder(dialect string) string {
+// GetPlaceholderStyle returns the default placeholderStyle of the given dialect.
+func (d __dialectPlaceholder) GetPlaceholderStyle(dialect string) placeholderStyle {
 	return d[dialect]
 }
 
@@ -100,24 +283,76 @@ func (d __dialectPlaceholder) CheckIfDialectIsSupported(dialect string) error {
 }
 
 var dialectPlaceholder = __dialectPlaceholder{
-	"mysql":    "?",
-	"postgres": "$%v", // This can become later in $1 see: Paginate() implementation for more.
+	"mysql":    questionPlaceholder{},
+	"mariadb":  questionPlaceholder{},
+	"postgres": dollarPlaceholder{},
 }
 
 type customOrderByClauses []orderByClause
 
 type orderByClause struct {
 	column, sorting string
+
+	// nulls is the placement of the NULL values of column: "FIRST", "LAST",
+	// or empty to use the default placement of the database.
+	nulls string
+
+	// args holds the arguments bound to the placeholders of column, if
+	// column is a custom sql expression like a CASE expression.
+	args []interface{}
+
+	// expression is true when column is a custom sql expression created by
+	// this package instead of a column name of the table.
+	expression bool
+
+	// caseColumn is the column of the CASE expression created by OrderByCase.
+	// The expression is created again when the sql command is built, so the
+	// column can be qualified and quoted like the rest of the columns.
+	caseColumn string
+
+	// renderColumn creates column again when the sql command is built for the
+	// other expressions created by this package, e.g. by OrderBySeededRandom,
+	// for the same reason as caseColumn. It receives Paginator's column method.
+	renderColumn func(column func(string) string) string
 }
 
 func (o orderByClause) String() string {
+	if o.nulls != "" {
+		return fmt.Sprintf("%s %s NULLS %s", o.column, o.sorting, o.nulls)
+	}
 	return fmt.Sprintf("%s %s", o.column, o.sorting)
 }
 
-func (clauses *customOrderByClauses) Clean(skipId string) {
+// render returns the orderByClause as an sql string for the given dialect.
+// Since mysql does not support NULLS FIRST and NULLS LAST, we emulate them
+// by sorting first by whether the column is NULL or not, e.g.:
+//
+//	null_int IS NULL ASC,null_int DESC
+func (o orderByClause) render(dialect string) string {
+	if o.nulls == "" || dialect == "postgres" {
+		return o.String()
+	}
+	isNullSorting := "ASC"
+	if o.nulls == "FIRST" {
+		isNullSorting = "DESC"
+	}
+	return fmt.Sprintf("%s IS NULL %s,%s %s", o.column, isNullSorting, o.column, o.sorting)
+}
+
+// args returns the arguments of all the custom "ORDER BY" clauses in the
+// same order in which the clauses will be rendered.
+func (clauses customOrderByClauses) args() []interface{} {
+	args := make([]interface{}, 0)
+	for _, c := range clauses {
+		args = append(args, c.args...)
+	}
+	return args
+}
+
+func (clauses *customOrderByClauses) Clean(skipIDs ...string) {
 	cleaned := make([]orderByClause, 0)
 	for _, c := range *clauses {
-		if c.column == skipId {
+		if isStringIn(c.column, skipIDs) {
 			continue
 		}
 		cleaned = append(cleaned, c)
@@ -2,6 +2,7 @@ package paginate
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -43,15 +44,23 @@ type RawWhereClause struct {
 	predicate string
 	args      []interface{}
 	dialect   string
+
+	// err is the first error found by the builder helpers, e.g. Eq, which
+	// is returned by Paginator.AddWhereClause.
+	err error
+
+	// render creates the predicate again when the sql command is built for
+	// the predicates created by this package, e.g. by the SoftDelete option,
+	// so their columns can be qualified and quoted like the rest of the
+	// columns. It receives Paginator's column method.
+	render func(column func(string) string) string
 }
 
-// String returns the RawWhereClause predicate without arguments as string.
+// String returns the RawWhereClause predicate without arguments as string. The
+// placeholders are rendered with the style of the dialect, e.g. "name = $1" for
+// postgres.
 func (raw RawWhereClause) String() string {
-	if raw.dialect == "postgres" {
-		pred := strings.Replace(raw.predicate, "?", "$%v", -1)
-		return fmt.Sprint(pred)
-	}
-	return raw.predicate
+	return renderPlaceholders(raw.predicate, raw.dialect, dialectPlaceholder.GetPlaceholderStyle(raw.dialect))
 }
 
 // AddPredicate adds the given predicate to a RawWhereClause instance.
@@ -68,3 +77,213 @@ func (raw *RawWhereClause) AddPredicate(predicate string) {
 func (raw *RawWhereClause) AddArg(v interface{}) {
 	raw.args = append(raw.args, v)
 }
+
+// Eq adds the predicate "column = ?" with the given value as argument
+// to the RawWhereClause instance. If the RawWhereClause already has a
+// predicate, the new one will be joined with AND, for example:
+//
+//  rawWhereSql.Eq("name", "ringo").Between("salary", 4000, 8000)
+//
+// will produce the predicate "name = ? AND salary BETWEEN ? AND ?". Invalid column
+// names will make Paginator.AddWhereClause return an error.
+func (raw *RawWhereClause) Eq(column string, v interface{}) *RawWhereClause {
+	return raw.and(column, column+" = ?", v)
+}
+
+// Like adds a predicate that matches the records whose column contains
+// the given value. The wildcard characters % and _ in the value will be
+// matched literally. For postgres the match is case-insensitive (ILIKE).
+func (raw *RawWhereClause) Like(column, value string) *RawWhereClause {
+	operator := "LIKE"
+	if raw.dialect == "postgres" {
+		operator = "ILIKE"
+	}
+	return raw.and(column, column+" "+operator+" ?", "%"+escapeLikeValue(value)+"%")
+}
+
+// Between adds the predicate "column BETWEEN ? AND ?" with the given
+// lower and upper bounds as arguments to the RawWhereClause instance.
+func (raw *RawWhereClause) Between(column string, lo, hi interface{}) *RawWhereClause {
+	return raw.and(column, column+" BETWEEN ? AND ?", lo, hi)
+}
+
+// orRegexp matches the OR operator of an sql predicate.
+var orRegexp = regexp.MustCompile(`(?i)\bOR\b`)
+
+// and joins the given predicate of the given column with the current predicate
+// using AND and adds the given arguments. A current predicate with OR is
+// parenthesized first, so the new predicate applies to all of it.
+func (raw *RawWhereClause) and(column, predicate string, args ...interface{}) *RawWhereClause {
+	if !columnNameRegexp.MatchString(column) {
+		if raw.err == nil {
+			raw.err = fmt.Errorf("paginate: invalid column name %q in where clause", column)
+		}
+		return raw
+	}
+	if raw.predicate != "" {
+		current := raw.predicate
+		if orRegexp.MatchString(current) {
+			current = parenthesize(current)
+		}
+		predicate = current + " AND " + predicate
+	}
+	raw.AddPredicate(predicate)
+	for _, arg := range args {
+		raw.AddArg(arg)
+	}
+	return raw
+}
+
+// NewConditionBuilder will give you a validated instance of a ConditionBuilder object.
+//
+// Use a ConditionBuilder whenever you want to build the predicate of a RawWhereClause
+// programmatically instead of writing it by hand. The column names given to the builder
+// are validated, and the values are always given to the database as arguments.
+//
+// Example of how to use the returned ConditionBuilder object:
+//
+//  ...
+//  paginator, _ := NewPaginator(MyTable{}, "mysql", *url)
+//  cb, err := NewConditionBuilder("mysql")
+//  if err != nil {
+//     // Handle error gracefully.
+//  }
+//
+//  rawWhereSql, err := cb.Where(cb.Or(
+//     cb.Eq("name", "ringo"),
+//     cb.And(cb.Gt("salary", 4000), cb.In("tenant_id", 1, 2)),
+//  ))
+//  if err != nil {
+//     // Handle error gracefully.
+//  }
+//
+//  err = paginator.AddWhereClause(rawWhereSql)
+//  if err != nil {
+//     // Handle error gracefully.
+//  }
+//
+// The above will produce the predicate "(name = ? OR (salary > ? AND tenant_id IN(?,?)))".
+func NewConditionBuilder(dialect string) (ConditionBuilder, error) {
+	if err := dialectPlaceholder.CheckIfDialectIsSupported(dialect); err != nil {
+		return ConditionBuilder{}, err
+	}
+	return ConditionBuilder{dialect: dialect}, nil
+}
+
+type ConditionBuilder struct {
+	dialect string
+}
+
+// Condition is an sql condition created by a ConditionBuilder. Conditions can
+// be combined with ConditionBuilder.And and ConditionBuilder.Or, and turned
+// into a RawWhereClause with ConditionBuilder.Where.
+type Condition struct {
+	predicate string
+	args      []interface{}
+	err       error
+}
+
+// Eq creates the condition "column = ?" with the given value as argument.
+func (cb ConditionBuilder) Eq(column string, v interface{}) Condition {
+	return cb.compare(column, "=", v)
+}
+
+// Ne creates the condition "column <> ?" with the given value as argument.
+func (cb ConditionBuilder) Ne(column string, v interface{}) Condition {
+	return cb.compare(column, "<>", v)
+}
+
+// Gt creates the condition "column > ?" with the given value as argument.
+func (cb ConditionBuilder) Gt(column string, v interface{}) Condition {
+	return cb.compare(column, ">", v)
+}
+
+// Gte creates the condition "column >= ?" with the given value as argument.
+func (cb ConditionBuilder) Gte(column string, v interface{}) Condition {
+	return cb.compare(column, ">=", v)
+}
+
+// Lt creates the condition "column < ?" with the given value as argument.
+func (cb ConditionBuilder) Lt(column string, v interface{}) Condition {
+	return cb.compare(column, "<", v)
+}
+
+// Lte creates the condition "column <= ?" with the given value as argument.
+func (cb ConditionBuilder) Lte(column string, v interface{}) Condition {
+	return cb.compare(column, "<=", v)
+}
+
+// In creates the condition "column IN(?,...)" with the given values as arguments.
+// At least one value should be given.
+func (cb ConditionBuilder) In(column string, values ...interface{}) Condition {
+	if !columnNameRegexp.MatchString(column) {
+		return Condition{err: fmt.Errorf("paginate: invalid column name %q in condition", column)}
+	}
+	if len(values) == 0 {
+		return Condition{err: fmt.Errorf("paginate: the IN condition of column %q requires at least one value", column)}
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
+	return Condition{predicate: column + " IN(" + placeholders + ")", args: values}
+}
+
+// And combines the given conditions with AND.
+func (cb ConditionBuilder) And(conditions ...Condition) Condition {
+	return cb.combine(_and, conditions)
+}
+
+// Or combines the given conditions with OR.
+func (cb ConditionBuilder) Or(conditions ...Condition) Condition {
+	return cb.combine(_or, conditions)
+}
+
+// Where returns a RawWhereClause with the predicate and the arguments of the
+// given condition, or the first error found while building the condition.
+func (cb ConditionBuilder) Where(condition Condition) (RawWhereClause, error) {
+	if condition.err != nil {
+		return RawWhereClause{}, condition.err
+	}
+	if condition.predicate == "" {
+		return RawWhereClause{}, fmt.Errorf("paginate: condition is empty")
+	}
+	raw := RawWhereClause{dialect: cb.dialect}
+	raw.AddPredicate(condition.predicate)
+	for _, arg := range condition.args {
+		raw.AddArg(arg)
+	}
+	return raw, nil
+}
+
+// compare creates the condition "column operator ?" with the given value as argument.
+func (cb ConditionBuilder) compare(column, operator string, v interface{}) Condition {
+	if !columnNameRegexp.MatchString(column) {
+		return Condition{err: fmt.Errorf("paginate: invalid column name %q in condition", column)}
+	}
+	return Condition{predicate: column + " " + operator + " ?", args: []interface{}{v}}
+}
+
+// combine joins the given conditions with the given conjunction. The combined
+// condition is wrapped in parentheses, so it can be safely nested in other
+// conditions or joined with the other where clauses of the Paginator.
+func (cb ConditionBuilder) combine(conjunction string, conditions []Condition) Condition {
+	if len(conditions) == 0 {
+		return Condition{err: fmt.Errorf("paginate: cannot combine zero conditions with %s", conjunction)}
+	}
+
+	predicates := make([]string, 0, len(conditions))
+	args := make([]interface{}, 0)
+	for _, c := range conditions {
+		if c.err != nil {
+			return Condition{err: c.err}
+		}
+		if c.predicate == "" {
+			return Condition{err: fmt.Errorf("paginate: condition is empty")}
+		}
+		predicates = append(predicates, c.predicate)
+		args = append(args, c.args...)
+	}
+
+	if len(predicates) == 1 {
+		return Condition{predicate: predicates[0], args: args}
+	}
+	return Condition{predicate: "(" + strings.Join(predicates, " "+conjunction+" ") + ")", args: args}
+}